@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"os"
+
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/cleanup"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+func setupCleanupCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Cleans up stale data in the deploy engine",
+		Long: `Triggers the cleanup of blueprint validations, change sets and events
+	that are older than the retention period configured for the deploy engine.
+
+	The cleanup is carried out by the deploy engine in the background,
+	this command reports each cleanup process that was triggered.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			validations, _ := confProvider.GetBool("cleanup.validations")
+			changesets, _ := confProvider.GetBool("cleanup.changesets")
+			events, _ := confProvider.GetBool("cleanup.events")
+			all, _ := confProvider.GetBool("cleanup.all")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+			opts := &cleanup.Options{
+				Validations: validations,
+				Changesets:  changesets,
+				Events:      events,
+				All:         all,
+			}
+
+			handler := handlers.NewCleanupHandler(deployEngine, opts, os.Stdout, logger)
+			return handler.Handle(context.TODO())
+		},
+	}
+
+	confProvider.AddFlags(
+		cleanupCmd.PersistentFlags(),
+		"cleanup.validations",
+		"cleanup.changesets",
+		"cleanup.events",
+		"cleanup.all",
+	)
+
+	rootCmd.AddCommand(cleanupCmd)
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func setupConfigCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspects and updates the configuration of the CLI",
+		Long: `Provides commands to inspect the effective configuration of the CLI
+	that has been resolved from flags, environment variables, the active profile,
+	the config file and defaults, along with commands to update config files.
+
+	Config files are not checked for unknown or invalid values when running
+	these commands so they can be used to fix invalid config files.`,
+		Annotations: map[string]string{
+			annotationSkipConfigChecks: "true",
+		},
+	}
+
+	setupConfigShowCommand(configCmd, confProvider)
+	setupConfigGetCommand(configCmd, confProvider)
+	setupConfigSetCommand(configCmd, confProvider)
+	setupConfigUnsetCommand(configCmd, confProvider)
+	setupConfigInitCommand(configCmd, confProvider)
+	setupConfigSchemaCommand(configCmd, confProvider)
+
+	rootCmd.AddCommand(configCmd)
+}
+
+func setupConfigShowCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Shows every effective config value along with where it came from",
+		Long: `Shows every effective config value along with the source it was resolved from,
+	this will be a flag, an environment variable, a profile or the top level of the
+	config file, a flag default or a default value.
+	Secret values and values resolved from "${file:...}" and "${cmd:...}"
+	references are masked.
+
+	Flags and environment variables provided when running this command are taken
+	into account, for example, "celerity config show --connect-protocol tcp".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			writeLoadedConfigFiles(confProvider)
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "NAME\tVALUE\tSOURCE")
+			for _, setting := range confProvider.Settings() {
+				fmt.Fprintf(
+					writer,
+					"%s\t%s\t%s\n",
+					setting.Name,
+					displayConfigValue(setting, confProvider.Redactor()),
+					setting.Source,
+				)
+			}
+			return writer.Flush()
+		},
+	}
+
+	configCmd.AddCommand(showCmd)
+}
+
+func setupConfigSchemaCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Writes a JSON Schema for CLI config files",
+		Long: `Writes a JSON Schema for celerity.config.* files to stdout,
+	editors can use the schema to provide completion and validation for config files.
+
+	For example, save the schema to a file with "celerity config schema > celerity.config.schema.json"
+	and reference it with a "#:schema ./celerity.config.schema.json" comment in a TOML config file
+	or a "# yaml-language-server: $schema=./celerity.config.schema.json" comment in a YAML config file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(confProvider.JSONSchema())
+		},
+	}
+
+	configCmd.AddCommand(schemaCmd)
+}
+
+func writeLoadedConfigFiles(confProvider *config.Provider) {
+	loadedConfigFiles := confProvider.LoadedConfigFiles()
+	if len(loadedConfigFiles) == 0 {
+		fmt.Fprintln(os.Stdout, "Config files: (none)")
+		fmt.Fprintln(os.Stdout)
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, "Config files (lowest to highest precedence):")
+	for _, configFile := range loadedConfigFiles {
+		fmt.Fprintf(os.Stdout, "  %s\n", configFile)
+	}
+	fmt.Fprintln(os.Stdout)
+}
+
+func displayConfigValue(setting config.Setting, redactor *config.Redactor) string {
+	if setting.Err != nil {
+		return redactor.Redact(fmt.Sprintf("(unresolved: %s)", setting.Err))
+	}
+
+	if setting.Value == "" {
+		return "(not set)"
+	}
+
+	return redactor.Redact(config.MaskValue(setting.Name, setting.Value))
+}
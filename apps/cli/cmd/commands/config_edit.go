@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func setupConfigGetCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	getCmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Shows the effective value of a config value",
+		Long: `Shows the effective value of a config value that has been resolved from flags,
+	environment variables, the active profile, the config file and defaults.
+	Values in a profile can be shown with a name in the form "profiles.<profile>.<name>".
+
+	Secret values are masked unless --reveal is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configName := args[0]
+			value, err := configValueForName(confProvider, configName)
+			if err != nil {
+				return err
+			}
+
+			reveal, _ := cmd.Flags().GetBool("reveal")
+			if !reveal {
+				value = confProvider.Redactor().Redact(config.MaskValue(configName, value))
+			}
+
+			fmt.Fprintln(os.Stdout, value)
+			return nil
+		},
+	}
+
+	getCmd.Flags().Bool(
+		"reveal",
+		false,
+		"Show the value of secrets instead of masking them.",
+	)
+
+	configCmd.AddCommand(getCmd)
+}
+
+func setupConfigSetCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	setCmd := &cobra.Command{
+		Use:   "set <name> <value>",
+		Short: "Sets a value in a config file",
+		Long: `Sets a value in a config file, this is the config file provided with --config,
+	the project config file closest to the working directory or a new celerity.config.toml
+	file in the working directory. The user-level config file is used when --user is set.
+	Values in a profile can be set with a name in the form "profiles.<profile>.<name>".
+
+	Values are provided in the same form as environment variables, where lists and maps are
+	comma-separated (e.g. "a,b,c" and "key1=value1,key2=value2").
+	Comments and the order of existing values are preserved in YAML and TOML config files
+	and the order of existing values is preserved in JSON config files.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configName := args[0]
+			value, err := confProvider.ParseValue(configName, args[1])
+			if err != nil {
+				return err
+			}
+
+			configFilePath, err := targetConfigFilePath(cmd, confProvider)
+			if err != nil {
+				return err
+			}
+
+			if err := config.SetFileValue(configFilePath, configName, value); err != nil {
+				return err
+			}
+
+			if config.IsSecret(configName) && !strings.Contains(args[1], "${") {
+				fmt.Fprintf(
+					os.Stderr,
+					"Warning: %q is stored in plain text, use an \"${env:NAME}\", \"${file:path}\" "+
+						"or \"${cmd:command}\" reference to avoid committing secrets to config files\n",
+					configName,
+				)
+			}
+
+			fmt.Fprintf(os.Stdout, "Set %q in %s\n", configName, configFilePath)
+			return nil
+		},
+	}
+
+	addUserConfigFlag(setCmd, "Set the value in the user-level config file.")
+
+	configCmd.AddCommand(setCmd)
+}
+
+func setupConfigUnsetCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	unsetCmd := &cobra.Command{
+		Use:   "unset <name>",
+		Short: "Removes a value from a config file",
+		Long: `Removes a value from a config file, the config file is chosen in the same way
+	as the "config set" command. Unknown config values can be removed
+	so this can be used to fix config files that fail to load.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configName := args[0]
+			configFilePath, err := targetConfigFilePath(cmd, confProvider)
+			if err != nil {
+				return err
+			}
+
+			removed, err := config.UnsetFileValue(configFilePath, configName)
+			if err != nil {
+				return err
+			}
+
+			if !removed {
+				return fmt.Errorf("%q is not set in %s", configName, configFilePath)
+			}
+
+			fmt.Fprintf(os.Stdout, "Removed %q from %s\n", configName, configFilePath)
+			return nil
+		},
+	}
+
+	addUserConfigFlag(unsetCmd, "Remove the value from the user-level config file.")
+
+	configCmd.AddCommand(unsetCmd)
+}
+
+func configValueForName(confProvider *config.Provider, configName string) (string, error) {
+	segments := strings.SplitN(configName, ".", 3)
+	if len(segments) == 3 && segments[0] == "profiles" {
+		values, hasProfile := confProvider.Profile(segments[1])
+		if !hasProfile {
+			return "", fmt.Errorf("profile %q is not defined in the config file", segments[1])
+		}
+		return values[segments[2]], nil
+	}
+
+	if _, isRegistered := confProvider.Key(configName); !isRegistered {
+		return "", fmt.Errorf("unknown config value %q", configName)
+	}
+
+	value, _ := confProvider.Lookup(configName)
+	return value, confProvider.ValueErrors()
+}
+
+func addUserConfigFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool("user", false, usage)
+}
+
+func targetConfigFilePath(cmd *cobra.Command, confProvider *config.Provider) (string, error) {
+	user, _ := cmd.Flags().GetBool("user")
+	if user {
+		return config.UserConfigFilePath()
+	}
+
+	return confProvider.ConfigFilePath(), nil
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/configui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupConfigInitCommand(configCmd *cobra.Command, confProvider *config.Provider) {
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Creates a config file with an interactive wizard",
+		Long: `Creates a config file with the connection and auth settings for a deploy engine
+	by taking you through an interactive wizard.
+
+	The config file is written to the path provided with --config or celerity.config.toml
+	in the working directory, the user-level config file is written when --user is set.
+	Secrets can be provided as "${env:NAME}", "${file:path}" or "${cmd:command}" references
+	so they are not stored in the config file.
+	File and command references are only resolved from the user-level config file
+	and the config file provided with --config unless --allow-cmd-references is set.`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			annotationCreatesConfigFile: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				return errors.New(
+					"config init requires an interactive terminal, " +
+						"use \"celerity config set\" to write config values in other environments",
+				)
+			}
+
+			configFilePath, err := initConfigFilePath(cmd)
+			if err != nil {
+				return err
+			}
+
+			force, _ := cmd.Flags().GetBool("force")
+			if _, err := os.Stat(configFilePath); err == nil && !force {
+				return fmt.Errorf(
+					"config file %s already exists, use --force to replace it "+
+						"or \"celerity config set\" to change values",
+					configFilePath,
+				)
+			}
+
+			app := configui.NewInitConfigModel(
+				func(configName string, value string) error {
+					_, err := confProvider.ParseValue(configName, value)
+					return err
+				},
+				styles.NewDefaultCelerityStyles(),
+			)
+			finalModel, err := tea.NewProgram(
+				app,
+				tea.WithOutput(confProvider.Redactor().File(os.Stdout)),
+			).Run()
+			if err != nil {
+				return err
+			}
+
+			finalApp := finalModel.(configui.InitConfigModel)
+			if finalApp.Cancelled() {
+				fmt.Fprintln(os.Stdout, "Config init cancelled, no config file was written.")
+				return nil
+			}
+
+			if err := writeInitConfigFile(confProvider, configFilePath, finalApp.Values()); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stdout, "Config file written to %s\n", configFilePath)
+			return nil
+		},
+	}
+
+	addUserConfigFlag(initCmd, "Write the user-level config file.")
+	initCmd.Flags().Bool(
+		"force",
+		false,
+		"Replace the config file if it already exists.",
+	)
+
+	configCmd.AddCommand(initCmd)
+}
+
+func initConfigFilePath(cmd *cobra.Command) (string, error) {
+	user, _ := cmd.Flags().GetBool("user")
+	if user {
+		return config.UserConfigFilePath()
+	}
+
+	configFlag := cmd.Flag("config")
+	if configFlag != nil && configFlag.Changed {
+		return configFlag.Value.String(), nil
+	}
+
+	return config.ConfigFileNames[0], nil
+}
+
+func writeInitConfigFile(
+	confProvider *config.Provider,
+	configFilePath string,
+	values []configui.Value,
+) error {
+	if err := os.MkdirAll(filepath.Dir(configFilePath), 0755); err != nil {
+		return err
+	}
+
+	header := ""
+	if !strings.HasSuffix(configFilePath, ".json") {
+		header = "# Celerity CLI config created with \"celerity config init\".\n" +
+			"# Run \"celerity config schema\" for a JSON Schema of all supported config values.\n\n"
+	}
+	if err := os.WriteFile(configFilePath, []byte(header), 0644); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		parsed, err := confProvider.ParseValue(value.ConfigName, value.Value)
+		if err != nil {
+			return err
+		}
+
+		if err := config.SetFileValue(configFilePath, value.ConfigName, parsed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,437 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/common/core"
+	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/instances"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
+)
+
+const (
+	remoteBlueprintFileUsage = "this can be a local path or a URI for a remote source " +
+		"such as \"s3://bucket/app.blueprint.yaml\", \"gcs://bucket/app.blueprint.yaml\", " +
+		"\"azureblob://container/app.blueprint.yaml\" or \"https://example.com/app.blueprint.yaml\". " +
+		"Local paths can only be used with a deploy engine running on this machine " +
+		"as the deploy engine API does not accept blueprint content inline."
+
+	varsUsage = "Blueprint variable overrides in the form \"name=value\" that take precedence over " +
+		"--var-file and the blueprint variables in the deploy config file. " +
+		"Values are parsed as integers, floats and booleans where possible, " +
+		"wrap a value in double quotes to use it as a string (e.g. 'tag=\"10\"'). " +
+		"This can be repeated to provide multiple variables."
+
+	varFilesUsage = "YAML, JSON or TOML files of blueprint variable overrides that take precedence over the blueprint " +
+		"variables in the deploy config file, where later files take precedence over earlier files. " +
+		"This can be repeated to provide multiple files."
+)
+
+// configKeys declares all of the config values supported by the CLI,
+// flags are only added to the commands that use them.
+func configKeys() []config.Key {
+	return []config.Key{
+		{
+			Name:        config.ProfileConfigName,
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_PROFILE",
+			Flag:        "profile",
+			Description: "The name of a profile in the config file to source connection, auth and deploy config values from.",
+		},
+		{
+			Name:    "deployConfigFile",
+			Type:    config.ValueTypeString,
+			Default: "celerity.deploy.json",
+			EnvVar:  "CELERITY_CLI_DEPLOY_CONFIG_FILE",
+			Flag:    "deploy-config-file",
+			Description: "The path to the deployment configuration YAML, JSON or TOML file that will be used as" +
+				" a source of blueprint variable overrides, provider configuration, " +
+				"transformer configuration and general configuration. " +
+				"The contents of this file is checked by the CLI and sent in requests to the deploy engine for " +
+				"validation, change staging and deployment. " +
+				"String values can contain \"${env:NAME}\", \"${file:path}\" and \"${cmd:command}\" references, " +
+				"file and command references are only resolved with --allow-cmd-references. " +
+				"When the default file does not exist, celerity.deploy.toml, .yaml or .yml will be used instead " +
+				"and no deploy config is sent if none of them exist.",
+		},
+		{
+			Name:   "env",
+			Type:   config.ValueTypeString,
+			EnvVar: "CELERITY_CLI_ENV",
+			Flag:   "env",
+			Description: "The name of an environment to layer the deploy config overlay file for over the " +
+				"deploy config file, for example, \"staging\" will load celerity.deploy.staging.json, " +
+				".toml, .yaml or .yml from the same directory as the deploy config file. " +
+				"Sections in the overlay file are deep merged with the deploy config file.",
+		},
+		{
+			Name: "connectProtocol",
+			Type: config.ValueTypeString,
+			// Connect to a local instance of the deploy engine
+			// via a unix socket by default.
+			Default: "unix",
+			EnvVar:  "CELERITY_CLI_CONNECT_PROTOCOL",
+			Flag:    "connect-protocol",
+			Description: "The protocol to connect to the deploy engine with, " +
+				"this can be either \"unix\" or \"tcp\". A unix socket can only be used on linux, macos, and other unix-like operating systems. " +
+				"To use a \"unix\" socket on windows, you will need to use WSL 2 or above.",
+			Enum: []string{"unix", "tcp"},
+		},
+		{
+			Name:        "engine.endpoint",
+			Type:        config.ValueTypeString,
+			Default:     deployengine.DefaultEndpoint,
+			EnvVar:      "CELERITY_CLI_ENGINE_ENDPOINT",
+			Flag:        "engine-endpoint",
+			Description: "The endpoint of the deploy engine api, this is used if --connect-protocol is set to \"tcp\"",
+			Aliases:     []string{"engineEndpoint"},
+		},
+		{
+			Name:    "engine.unixSocket",
+			Type:    config.ValueTypeString,
+			Default: deployengine.DefaultUnixDomainSocket,
+			EnvVar:  "CELERITY_CLI_ENGINE_UNIX_SOCKET",
+			Flag:    "engine-unix-socket",
+			Description: "The path of the unix socket to connect to the deploy engine with, " +
+				"this is used if --connect-protocol is set to \"unix\"",
+		},
+		{
+			Name:    "engine.authMethod",
+			Type:    config.ValueTypeString,
+			Default: engine.AuthMethodNone,
+			EnvVar:  "CELERITY_CLI_ENGINE_AUTH_METHOD",
+			Flag:    "engine-auth-method",
+			Description: "The method used to authenticate with the deploy engine, " +
+				"this can be one of \"none\", \"api-key\", \"oauth2\" or \"celerity-signature-v1\". " +
+				"\"none\" can be used for a local deploy engine that does not require authentication. " +
+				"Credentials for the chosen method are sourced from the config file or environment variables.",
+			Enum: []string{
+				engine.AuthMethodNone,
+				engine.AuthMethodAPIKey,
+				engine.AuthMethodOAuth2,
+				engine.AuthMethodCeleritySignatureV1,
+			},
+		},
+		// Credentials for the deploy engine auth methods are deliberately not
+		// exposed as flags to avoid secrets ending up in shell history.
+		{
+			Name:        "engine.auth.apiKey",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_API_KEY",
+			Description: "The API key used to authenticate with the deploy engine for the \"api-key\" auth method.",
+		},
+		{
+			Name:        "engine.auth.oauth2.providerBaseURL",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_OAUTH2_PROVIDER_BASE_URL",
+			Description: "The base URL of the OAuth2 or OIDC provider used to discover the token endpoint.",
+		},
+		{
+			Name:        "engine.auth.oauth2.tokenEndpoint",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_OAUTH2_TOKEN_ENDPOINT",
+			Description: "The token endpoint of the OAuth2 or OIDC provider, this takes precedence over the provider base URL.",
+		},
+		{
+			Name:        "engine.auth.oauth2.clientID",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_OAUTH2_CLIENT_ID",
+			Description: "The client ID used to obtain an access token for the \"oauth2\" auth method.",
+		},
+		{
+			Name:        "engine.auth.oauth2.clientSecret",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_OAUTH2_CLIENT_SECRET",
+			Description: "The client secret used to obtain an access token for the \"oauth2\" auth method.",
+		},
+		{
+			Name:        "engine.auth.signatureV1.keyID",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_SIGNATURE_V1_KEY_ID",
+			Description: "The ID of the key pair used for the \"celerity-signature-v1\" auth method.",
+		},
+		{
+			Name:        "engine.auth.signatureV1.secretKey",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_ENGINE_SIGNATURE_V1_SECRET_KEY",
+			Description: "The secret key of the key pair used for the \"celerity-signature-v1\" auth method.",
+		},
+		{
+			Name:   "engine.auth.signatureV1.customHeaders",
+			Type:   config.ValueTypeStringList,
+			EnvVar: "CELERITY_CLI_ENGINE_SIGNATURE_V1_CUSTOM_HEADERS",
+			Description: "A list of headers to include in the signed message for the \"celerity-signature-v1\" auth method, " +
+				"this is a comma-separated list when provided as an environment variable.",
+		},
+		{
+			Name:        "skipPluginConfigValidation",
+			Type:        config.ValueTypeBool,
+			EnvVar:      "CELERITY_CLI_SKIP_PLUGIN_CONFIG_VALIDATION",
+			Flag:        "skip-plugin-config-validation",
+			Description: "Skip validation of the plugin-specific entries in the deploy configuration file for commands that interact with the deploy engine.",
+		},
+		{
+			Name:        "init.language",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_INIT_LANGUAGE",
+			Flag:        "language",
+			Shorthand:   "l",
+			Description: fmt.Sprintf("The programming language/framework you want to use for the new project. Can be one of %s.", supportedLanguagesText()),
+			Enum:        consts.SupportedLanguages,
+			Aliases:     []string{"initLanguage"},
+		},
+		{
+			Name:      "validate.blueprintFile",
+			Type:      config.ValueTypeStringList,
+			Default:   "app.blueprint.yaml",
+			EnvVar:    "CELERITY_CLI_VALIDATE_BLUEPRINT_FILE",
+			Flag:      "blueprint-file",
+			Shorthand: "b",
+			Description: "The blueprint files to use in the validation process, " + remoteBlueprintFileUsage +
+				" Local paths can be glob patterns such as 'infra/**/*.blueprint.yaml'. " +
+				"This can be repeated to validate multiple blueprint files.",
+			Aliases: []string{"validateBlueprintFile"},
+		},
+		{
+			Name:    "validate.format",
+			Type:    config.ValueTypeString,
+			Default: string(validate.FormatText),
+			EnvVar:  "CELERITY_CLI_VALIDATE_FORMAT",
+			Flag:    "format",
+			Description: "The format to write validation diagnostics in, " +
+				"one of \"text\", \"json\", \"sarif\", \"junit\", \"github\" or \"checkstyle\". " +
+				"Formats other than \"text\" are always written without the interactive UI.",
+			Enum: core.Map(validate.SupportedFormats, formatToString),
+		},
+		{
+			Name:    "validate.failOn",
+			Type:    config.ValueTypeString,
+			Default: "error",
+			EnvVar:  "CELERITY_CLI_VALIDATE_FAIL_ON",
+			Flag:    "fail-on",
+			Description: "The minimum severity of diagnostics that will cause validation to fail " +
+				"with an exit code of 2, one of \"error\", \"warning\" or \"info\".",
+			Enum: []string{"error", "warning", "info"},
+		},
+		{
+			Name:        "validate.vars",
+			Type:        config.ValueTypeStringMap,
+			EnvVar:      "CELERITY_CLI_VALIDATE_VARS",
+			Flag:        "var",
+			Description: varsUsage,
+		},
+		{
+			Name:        "validate.varFiles",
+			Type:        config.ValueTypeStringList,
+			EnvVar:      "CELERITY_CLI_VALIDATE_VAR_FILES",
+			Flag:        "var-file",
+			Description: varFilesUsage,
+		},
+		{
+			Name:    "validate.hyperlinks",
+			Type:    config.ValueTypeString,
+			Default: validate.HyperlinksAuto,
+			EnvVar:  "CELERITY_CLI_VALIDATE_HYPERLINKS",
+			Flag:    "hyperlinks",
+			Description: "Whether blueprint file locations in diagnostics are written as clickable " +
+				"hyperlinks for terminals that support them, one of \"auto\", \"always\" or \"never\". " +
+				"With \"auto\", hyperlinks are only written when the output is a terminal.",
+			Enum: []string{validate.HyperlinksAuto, validate.HyperlinksAlways, validate.HyperlinksNever},
+		},
+		{
+			Name:    "validate.parallelism",
+			Type:    config.ValueTypeInt,
+			Default: strconv.Itoa(validate.DefaultParallelism),
+			EnvVar:  "CELERITY_CLI_VALIDATE_PARALLELISM",
+			Flag:    "parallelism",
+			Description: "The maximum number of blueprint files that are validated at the same time " +
+				"when multiple blueprint files are provided.",
+		},
+		{
+			Name:   "validate.watch",
+			Type:   config.ValueTypeBool,
+			EnvVar: "CELERITY_CLI_VALIDATE_WATCH",
+			Flag:   "watch",
+			Description: "Validate the blueprint again each time the blueprint file, " +
+				"its child blueprints or the deploy config files change. " +
+				"This can only be used with the \"text\" format and a single local blueprint file.",
+		},
+		{
+			Name:        "stage.blueprintFile",
+			Type:        config.ValueTypeString,
+			Default:     "app.blueprint.yaml",
+			EnvVar:      "CELERITY_CLI_STAGE_BLUEPRINT_FILE",
+			Flag:        "blueprint-file",
+			Shorthand:   "b",
+			Description: "The blueprint file to stage changes for, " + remoteBlueprintFileUsage,
+		},
+		{
+			Name:        "stage.instanceID",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_STAGE_INSTANCE_ID",
+			Flag:        "instance-id",
+			Description: "The ID of an existing blueprint instance to stage changes for.",
+		},
+		{
+			Name:   "stage.instanceName",
+			Type:   config.ValueTypeString,
+			EnvVar: "CELERITY_CLI_STAGE_INSTANCE_NAME",
+			Flag:   "instance-name",
+			Description: "The name of an existing blueprint instance to stage changes for, " +
+				"this is ignored if --instance-id is set.",
+		},
+		{
+			Name:   "stage.destroy",
+			Type:   config.ValueTypeBool,
+			EnvVar: "CELERITY_CLI_STAGE_DESTROY",
+			Flag:   "destroy",
+			Description: "Stage changes for destroying an existing blueprint instance, " +
+				"this requires --instance-id or --instance-name to be set.",
+		},
+		{
+			Name:        "stage.vars",
+			Type:        config.ValueTypeStringMap,
+			EnvVar:      "CELERITY_CLI_STAGE_VARS",
+			Flag:        "var",
+			Description: varsUsage,
+		},
+		{
+			Name:        "stage.varFiles",
+			Type:        config.ValueTypeStringList,
+			EnvVar:      "CELERITY_CLI_STAGE_VAR_FILES",
+			Flag:        "var-file",
+			Description: varFilesUsage,
+		},
+		{
+			Name:        "deploy.blueprintFile",
+			Type:        config.ValueTypeString,
+			Default:     "app.blueprint.yaml",
+			EnvVar:      "CELERITY_CLI_DEPLOY_BLUEPRINT_FILE",
+			Flag:        "blueprint-file",
+			Shorthand:   "b",
+			Description: "The blueprint file to deploy, " + remoteBlueprintFileUsage,
+		},
+		{
+			Name:        "deploy.instanceID",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_DEPLOY_INSTANCE_ID",
+			Flag:        "instance-id",
+			Description: "The ID of an existing blueprint instance to update.",
+		},
+		{
+			Name:   "deploy.instanceName",
+			Type:   config.ValueTypeString,
+			EnvVar: "CELERITY_CLI_DEPLOY_INSTANCE_NAME",
+			Flag:   "instance-name",
+			Description: "The name of an existing blueprint instance to update, " +
+				"this is ignored if --instance-id is set.",
+		},
+		{
+			Name:   "deploy.changesetID",
+			Type:   config.ValueTypeString,
+			EnvVar: "CELERITY_CLI_DEPLOY_CHANGE_SET_ID",
+			Flag:   "change-set-id",
+			Description: "The ID of a change set created with the stage command to deploy, " +
+				"when not set, changes will be staged before deploying.",
+		},
+		{
+			Name:        "deploy.vars",
+			Type:        config.ValueTypeStringMap,
+			EnvVar:      "CELERITY_CLI_DEPLOY_VARS",
+			Flag:        "var",
+			Description: varsUsage,
+		},
+		{
+			Name:        "deploy.varFiles",
+			Type:        config.ValueTypeStringList,
+			EnvVar:      "CELERITY_CLI_DEPLOY_VAR_FILES",
+			Flag:        "var-file",
+			Description: varFilesUsage,
+		},
+		{
+			Name:        "destroy.instanceID",
+			Type:        config.ValueTypeString,
+			EnvVar:      "CELERITY_CLI_DESTROY_INSTANCE_ID",
+			Flag:        "instance-id",
+			Description: "The ID of the blueprint instance to destroy.",
+		},
+		{
+			Name:   "destroy.instanceName",
+			Type:   config.ValueTypeString,
+			EnvVar: "CELERITY_CLI_DESTROY_INSTANCE_NAME",
+			Flag:   "instance-name",
+			Description: "The name of the blueprint instance to destroy, " +
+				"this is ignored if --instance-id is set.",
+		},
+		{
+			Name: "destroy.force",
+			Type: config.ValueTypeBool,
+			Flag: "force",
+			Description: "Allow a protected blueprint instance to be destroyed, " +
+				"you will be asked to enter the instance name to confirm.",
+		},
+		{
+			Name:   "protectedInstances",
+			Type:   config.ValueTypeStringList,
+			EnvVar: "CELERITY_CLI_PROTECTED_INSTANCES",
+			Description: "A list of blueprint instance names that can not be destroyed " +
+				"without --force and confirmation, this is a comma-separated " +
+				"list when provided as an environment variable.",
+		},
+		{
+			Name:      "instance.output",
+			Type:      config.ValueTypeString,
+			Default:   string(instances.OutputFormatText),
+			EnvVar:    "CELERITY_CLI_INSTANCE_OUTPUT",
+			Flag:      "output",
+			Shorthand: "o",
+			Description: "The format to write blueprint instance information in, " +
+				"one of \"text\", \"json\" or \"yaml\".",
+			Enum: []string{
+				string(instances.OutputFormatText),
+				string(instances.OutputFormatJSON),
+				string(instances.OutputFormatYAML),
+			},
+		},
+		{
+			Name:        "cleanup.validations",
+			Type:        config.ValueTypeBool,
+			Flag:        "validations",
+			Description: "Clean up blueprint validations.",
+		},
+		{
+			Name:        "cleanup.changesets",
+			Type:        config.ValueTypeBool,
+			Flag:        "changesets",
+			Description: "Clean up change sets.",
+		},
+		{
+			Name:        "cleanup.events",
+			Type:        config.ValueTypeBool,
+			Flag:        "events",
+			Description: "Clean up events for blueprint validations, change staging and deployments.",
+		},
+		{
+			Name:        "cleanup.all",
+			Type:        config.ValueTypeBool,
+			Flag:        "all",
+			Description: "Clean up blueprint validations, change sets and events.",
+		},
+	}
+}
+
+func supportedLanguagesText() string {
+	return strings.Join(
+		core.Map(consts.SupportedLanguages, quote),
+		", ",
+	)
+}
+
+func formatToString(format validate.Format, _ int) string {
+	return string(format)
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/deployui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupDeployCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploys a Celerity blueprint",
+		Long: `Deploys a Celerity blueprint as a new blueprint instance or
+	updates an existing blueprint instance, showing the live progress of each
+	resource, child blueprint and link as it is deployed.
+
+	If an instance ID or name is provided, the existing blueprint instance will be updated,
+	otherwise a new blueprint instance will be created.
+	Changes will be staged before deploying unless a change set ID is provided.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			blueprintFile, _ := confProvider.GetString("deploy.blueprintFile")
+			instanceID, _ := confProvider.GetString("deploy.instanceID")
+			instanceName, _ := confProvider.GetString("deploy.instanceName")
+			changesetID, _ := confProvider.GetString("deploy.changesetID")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			if _, err := blueprint.ParseLocation(blueprintFile); err != nil {
+				return err
+			}
+
+			deployConfig, err := loadDeployConfigWithVars(confProvider, "deploy", logger)
+			if err != nil {
+				return err
+			}
+
+			opts := &deploy.Options{
+				BlueprintFile: blueprintFile,
+				InstanceID:    instanceID,
+				InstanceName:  instanceName,
+				ChangesetID:   changesetID,
+				DeployConfig:  deployConfig,
+				RemoteEngine:  engine.IsRemote(confProvider),
+			}
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				handler := handlers.NewDeployHandler(
+					deployEngine,
+					opts,
+					os.Stdout,
+					logger,
+				)
+				return handler.Handle(context.TODO())
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			styles := styles.NewDefaultCelerityStyles()
+			app, err := deployui.NewDeployApp(deployEngine, logger, opts, styles)
+			if err != nil {
+				return err
+			}
+			finalModel, err := tea.NewProgram(
+				app,
+				tea.WithOutput(confProvider.Redactor().File(os.Stdout)),
+			).Run()
+			if err != nil {
+				return err
+			}
+			finalApp := finalModel.(deployui.MainModel)
+
+			if finalApp.Error != nil {
+				return finalApp.Error
+			}
+
+			return nil
+		},
+	}
+
+	confProvider.AddFlags(
+		deployCmd.PersistentFlags(),
+		"deploy.blueprintFile",
+		"deploy.instanceID",
+		"deploy.instanceName",
+		"deploy.changesetID",
+		"deploy.vars",
+		"deploy.varFiles",
+	)
+
+	rootCmd.AddCommand(deployCmd)
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deployconfig"
+	"go.uber.org/zap"
+)
+
+// loadDeployConfig loads and checks the deploy config file so that problems
+// are reported before any requests are made to the deploy engine.
+// The default deploy config file is optional, a deploy config file
+// that has been explicitly provided must exist.
+func loadDeployConfig(confProvider *config.Provider) (*types.BlueprintOperationConfig, error) {
+	files, err := loadDeployConfigFiles(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployconfig.Merge(files...), nil
+}
+
+func loadDeployConfigFiles(confProvider *config.Provider) ([]*deployconfig.File, error) {
+	deployConfigFile, opts := deployConfigOptions(confProvider)
+	if err := confProvider.ValueErrors(); err != nil {
+		return nil, err
+	}
+
+	return deployconfig.LoadFiles(deployConfigFile, opts)
+}
+
+func deployConfigOptions(confProvider *config.Provider) (string, *deployconfig.Options) {
+	deployConfigFile, isDefault := confProvider.GetString("deployConfigFile")
+	env, _ := confProvider.GetString("env")
+	skipPluginConfigValidation, _ := confProvider.GetBool("skipPluginConfigValidation")
+	return deployConfigFile, &deployconfig.Options{
+		Required:                   !isDefault,
+		Environment:                env,
+		SkipPluginConfigValidation: skipPluginConfigValidation,
+		Interpolator:               confProvider.Interpolator(),
+	}
+}
+
+// deployConfigWatchPaths returns the paths of the deploy config files
+// and var files for a command that are watched for changes in watch mode.
+func deployConfigWatchPaths(confProvider *config.Provider, commandName string) []string {
+	deployConfigFile, opts := deployConfigOptions(confProvider)
+	varFiles, _ := confProvider.GetStringSlice(commandName + ".varFiles")
+	return append(deployconfig.WatchPaths(deployConfigFile, opts), varFiles...)
+}
+
+// loadDeployConfigWithVars loads the deploy config files and merges the
+// blueprint variable overrides provided for a command over them,
+// variables from --var take precedence over variables from --var-file.
+// The source of each blueprint variable is written to the debug log.
+func loadDeployConfigWithVars(
+	confProvider *config.Provider,
+	commandName string,
+	logger *zap.Logger,
+) (*types.BlueprintOperationConfig, error) {
+	files, err := loadDeployConfigFiles(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	varFiles, _ := confProvider.GetStringSlice(commandName + ".varFiles")
+	varsConfigName := commandName + ".vars"
+	vars, _ := confProvider.GetStringMap(varsConfigName)
+	if err := confProvider.ValueErrors(); err != nil {
+		return nil, err
+	}
+
+	layers := []*deployconfig.VariableLayer{}
+	for _, file := range files {
+		layers = append(layers, deployconfig.FileVariables(file))
+	}
+
+	for _, varFile := range varFiles {
+		layer, err := deployconfig.LoadVarFile(varFile, confProvider.Interpolator())
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	_, varsSource := confProvider.Lookup(varsConfigName)
+	varsLayer, err := deployconfig.ParseVars(fmt.Sprintf("vars from %s", varsSource), vars)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, varsLayer)
+
+	deployConfig, sources := deployconfig.MergeVariables(
+		deployconfig.Merge(files...),
+		layers...,
+	)
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
+		logger.Debug(
+			"resolved blueprint variable",
+			zap.String("variable", name),
+			zap.String("source", sources[name]),
+		)
+	}
+
+	return deployConfig, nil
+}
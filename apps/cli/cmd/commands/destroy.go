@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/destroy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/destroyui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupDestroyCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	destroyCmd := &cobra.Command{
+		Use:   "destroy",
+		Short: "Destroys a blueprint instance",
+		Long: `Destroys an existing blueprint instance, showing the live progress of each
+	resource, child blueprint and link as it is removed.
+
+	Blueprint instances listed in the "protectedInstances" config value can only be
+	destroyed with the --force flag and after confirming the instance name.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			instanceID, _ := confProvider.GetString("destroy.instanceID")
+			instanceName, _ := confProvider.GetString("destroy.instanceName")
+			force, _ := confProvider.GetBool("destroy.force")
+			protectedInstances, _ := confProvider.GetStringSlice("protectedInstances")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+			deployConfig, err := loadDeployConfig(confProvider)
+			if err != nil {
+				return err
+			}
+
+			opts := &destroy.Options{
+				InstanceID:         instanceID,
+				InstanceName:       instanceName,
+				Force:              force,
+				ProtectedInstances: protectedInstances,
+				DeployConfig:       deployConfig,
+			}
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				handler := handlers.NewDestroyHandler(
+					deployEngine,
+					opts,
+					// Confirmation of protected instance names
+					// is read from stdin when not in a terminal.
+					os.Stdin,
+					os.Stdout,
+					logger,
+				)
+				return handler.Handle(context.TODO())
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			styles := styles.NewDefaultCelerityStyles()
+			app, err := destroyui.NewDestroyApp(deployEngine, logger, opts, styles)
+			if err != nil {
+				return err
+			}
+			finalModel, err := tea.NewProgram(
+				app,
+				tea.WithOutput(confProvider.Redactor().File(os.Stdout)),
+			).Run()
+			if err != nil {
+				return err
+			}
+			finalApp := finalModel.(destroyui.MainModel)
+
+			if finalApp.Error != nil {
+				return finalApp.Error
+			}
+
+			return nil
+		},
+	}
+
+	confProvider.AddFlags(
+		destroyCmd.PersistentFlags(),
+		"destroy.instanceID",
+		"destroy.instanceName",
+		"destroy.force",
+	)
+
+	rootCmd.AddCommand(destroyCmd)
+}
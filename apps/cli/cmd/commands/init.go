@@ -3,28 +3,27 @@ package commands
 import (
 	"fmt"
 	"slices"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/initui"
-	"github.com/newstack-cloud/bluelink/libs/common/core"
 	"github.com/spf13/cobra"
 )
 
 func setupInitCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
-	supportedLanguagesStr := strings.Join(
-		core.Map(consts.SupportedLanguages, quote),
-		", ",
-	)
+	supportedLanguagesStr := supportedLanguagesText()
 	initCmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialises a new Celerity project",
 		Long: `Initialises a new Celerity project, this will take you through an interactive set up
 		process but you can also use flags to skip certain prompts.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			lang, _ := confProvider.GetString("initLanguage")
+			lang, _ := confProvider.GetString("init.language")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
 			err := validateLanguage(lang, supportedLanguagesStr)
 			if err != nil {
 				return err
@@ -35,14 +34,10 @@ func setupInitCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
 		},
 	}
 
-	initCmd.PersistentFlags().StringP(
-		"language",
-		"l",
-		"",
-		fmt.Sprintf("The programming language/framework you want to use for the new project. Can be one of %s.", supportedLanguagesStr),
+	confProvider.AddFlags(
+		initCmd.PersistentFlags(),
+		"init.language",
 	)
-	confProvider.BindPFlag("initLanguage", initCmd.PersistentFlags().Lookup("language"))
-	confProvider.BindEnvVar("initLanguage", "CELERITY_CLI_INIT_LANGUAGE")
 
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"os"
+
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/instances"
+	"github.com/spf13/cobra"
+)
+
+func setupInstanceCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	instanceCmd := &cobra.Command{
+		Use:   "instance",
+		Short: "Inspects deployed blueprint instances",
+		Long: `Provides commands to inspect the current state and exports
+	of blueprint instances that have been deployed with the deploy engine.`,
+	}
+
+	confProvider.AddFlags(
+		instanceCmd.PersistentFlags(),
+		"instance.output",
+	)
+
+	setupInstanceGetCommand(instanceCmd, confProvider)
+	setupInstanceExportsCommand(instanceCmd, confProvider)
+
+	rootCmd.AddCommand(instanceCmd)
+}
+
+func setupInstanceGetCommand(instanceCmd *cobra.Command, confProvider *config.Provider) {
+	getCmd := &cobra.Command{
+		Use:   "get <instance-id>",
+		Short: "Shows the current state of a blueprint instance",
+		Long: `Shows the current state of a blueprint instance, including the status
+	of each resource, link and child blueprint along with the instance exports.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := instanceOutputFormat(confProvider)
+			if err != nil {
+				return err
+			}
+
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			handler := handlers.NewInstanceGetHandler(
+				deployEngine,
+				args[0],
+				format,
+				os.Stdout,
+				logger,
+			)
+			return handler.Handle(context.TODO())
+		},
+	}
+
+	instanceCmd.AddCommand(getCmd)
+}
+
+func setupInstanceExportsCommand(instanceCmd *cobra.Command, confProvider *config.Provider) {
+	exportsCmd := &cobra.Command{
+		Use:   "exports <instance-id> [export-path]",
+		Short: "Shows the exports of a blueprint instance",
+		Long: `Shows the exports of a blueprint instance.
+
+	An optional dotted path can be provided to select a single value,
+	for example "api.endpoints[0].url" selects the "url" field of the first
+	item in the "endpoints" array of the "api" export.
+	Scalar values selected in the text format are written as they are
+	so they can be used directly in scripts.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := instanceOutputFormat(confProvider)
+			if err != nil {
+				return err
+			}
+
+			exportPath := ""
+			if len(args) == 2 {
+				exportPath = args[1]
+			}
+
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			handler := handlers.NewInstanceExportsHandler(
+				deployEngine,
+				args[0],
+				exportPath,
+				format,
+				os.Stdout,
+				logger,
+			)
+			return handler.Handle(context.TODO())
+		},
+	}
+
+	instanceCmd.AddCommand(exportsCmd)
+}
+
+func instanceOutputFormat(confProvider *config.Provider) (instances.OutputFormat, error) {
+	output, _ := confProvider.GetString("instance.output")
+	if err := confProvider.ValueErrors(); err != nil {
+		return "", err
+	}
+	return instances.ParseOutputFormat(output)
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func setupProfileCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manages connection profiles",
+		Long: `Manages named profiles in the CLI config file.
+
+	A profile bundles the connection, auth and deploy config settings
+	for a deploy engine under the "profiles" section of the config file.
+	For example, in a TOML config file:
+
+	  profile = "local"
+
+	  [profiles.local]
+	  connectProtocol = "unix"
+
+	  [profiles.staging]
+	  connectProtocol = "tcp"
+	  deployConfigFile = "celerity.deploy.staging.json"
+
+	  [profiles.staging.engine]
+	  endpoint = "https://staging.engine.example.com"
+	  authMethod = "api-key"
+
+	Values in the active profile take precedence over values at the top level
+	of the config file, flags and environment variables take precedence over both.
+
+	Config files are not checked for unknown or invalid values when running
+	these commands so they can be used to switch away from a profile that is
+	no longer defined.`,
+		Annotations: map[string]string{
+			annotationSkipConfigChecks: "true",
+		},
+	}
+
+	setupProfileListCommand(profileCmd, confProvider)
+	setupProfileUseCommand(profileCmd, confProvider)
+	setupProfileShowCommand(profileCmd, confProvider)
+
+	rootCmd.AddCommand(profileCmd)
+}
+
+func setupProfileListCommand(profileCmd *cobra.Command, confProvider *config.Provider) {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the profiles defined in the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			activeProfile := confProvider.ActiveProfile()
+			for _, profile := range confProvider.Profiles() {
+				marker := " "
+				if profile == activeProfile {
+					marker = "*"
+				}
+				fmt.Fprintf(os.Stdout, "%s %s\n", marker, profile)
+			}
+			return nil
+		},
+	}
+
+	profileCmd.AddCommand(listCmd)
+}
+
+func setupProfileUseCommand(profileCmd *cobra.Command, confProvider *config.Provider) {
+	useCmd := &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Sets the active profile in the config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := args[0]
+			if !slices.Contains(confProvider.Profiles(), profile) {
+				return fmt.Errorf("profile %q is not defined in the config file", profile)
+			}
+
+			err := config.SetFileValue(
+				confProvider.ConfigFilePath(),
+				config.ProfileConfigName,
+				profile,
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stdout, "Switched to profile %q\n", profile)
+			return nil
+		},
+	}
+
+	profileCmd.AddCommand(useCmd)
+}
+
+func setupProfileShowCommand(profileCmd *cobra.Command, confProvider *config.Provider) {
+	showCmd := &cobra.Command{
+		Use:   "show [profile]",
+		Short: "Shows the config values of a profile",
+		Long: `Shows the config values of a profile, the active profile is shown
+	when a profile name is not provided. Secret values are masked.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := confProvider.ActiveProfile()
+			if len(args) == 1 {
+				profile = args[0]
+			}
+
+			if profile == "" {
+				return fmt.Errorf("no profile is active, provide the name of a profile to show")
+			}
+
+			values, hasProfile := confProvider.Profile(profile)
+			if !hasProfile {
+				return fmt.Errorf("profile %q is not defined in the config file", profile)
+			}
+
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			fmt.Fprintf(os.Stdout, "Profile: %s\n", profile)
+			for _, name := range names {
+				fmt.Fprintf(os.Stdout, "  %s = %s\n", name, config.MaskValue(name, values[name]))
+			}
+			return nil
+		},
+	}
+
+	profileCmd.AddCommand(showCmd)
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
@@ -13,6 +14,7 @@ import (
 
 func NewRootCmd() *cobra.Command {
 	var configFile string
+	var allowCmdReferences bool
 
 	confProvider := config.NewProvider()
 
@@ -24,12 +26,39 @@ func NewRootCmd() *cobra.Command {
 This CLI validates, builds, and deploys celerity applications
 along with blueprints used for Infrastructure as Code.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := confProvider.LoadConfigFile(configFile); err != nil {
+			if allowCmdReferences {
+				confProvider.Interpolator().AllowAllReferences()
+			}
+
+			err := confProvider.LoadConfig(configFile)
+			if errors.Is(err, config.ErrConfigFileNotFound) && createsConfigFile(cmd) {
+				err = nil
+			}
+			if err != nil {
+				return err
+			}
+			for _, warning := range confProvider.Warnings() {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+
+			if skipsConfigChecks(cmd) {
+				return nil
+			}
+
+			if err := confProvider.CheckConfig(); err != nil {
+				return err
+			}
+
+			if err := confProvider.CheckActiveProfile(); err != nil {
 				return err
 			}
 
 			connectProtocol, _ := confProvider.GetString("connectProtocol")
-			err := validateConnectProtocol(connectProtocol)
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			err = validateConnectProtocol(connectProtocol)
 			if err != nil {
 				return err
 			}
@@ -41,61 +70,79 @@ along with blueprints used for Infrastructure as Code.`,
 	rootCmd.SetUsageTemplate(utils.UsageTemplate)
 	rootCmd.SetHelpTemplate(utils.HelpTemplate)
 
-	rootCmd.Flags().StringVarP(
+	rootCmd.PersistentFlags().StringVarP(
 		&configFile,
 		"config",
 		"c",
-		"celerity.config.toml",
-		"Specify a config file to source config from as an alternative to flags",
-	)
-
-	rootCmd.PersistentFlags().String(
-		"deploy-config-file",
-		"celerity.deploy.json",
-		"The path to the deployment configuration JSON file that will be used as"+
-			" a source of blueprint variable overrides, provider configuration, "+
-			"transformer configuration and general configuration. "+
-			"The contents of this file is sent in requests to the deploy engine for "+
-			"validation, change staging and deployment.",
-	)
-	confProvider.BindPFlag("deployConfigFile", rootCmd.PersistentFlags().Lookup("deploy-config-file"))
-	confProvider.BindEnvVar("deployConfigFile", "CELERITY_CLI_DEPLOY_CONFIG_FILE")
-
-	rootCmd.PersistentFlags().String(
-		"connect-protocol",
-		// Connect to a local instance of the deploy engine
-		// via a unix socket by default.
-		"unix",
-		"The protocol to connect to the deploy engine with, "+
-			"this can be either \"unix\" or \"tcp\". A unix socket can only be used on linux, macos, and other unix-like operating systems. "+
-			"To use a \"unix\" socket on windows, you will need to use WSL 2 or above.",
+		"",
+		"Specify a config file to source config from as an alternative to flags. "+
+			"When not set, celerity.config.toml, .yaml, .yml or .json files are discovered in each directory "+
+			"from the working directory up to the project root, where files closer to the working directory "+
+			"take precedence. A user-level config file in $XDG_CONFIG_HOME/celerity/ is merged with the lowest precedence.",
 	)
-	confProvider.BindPFlag("connectProtocol", rootCmd.PersistentFlags().Lookup("connect-protocol"))
-	confProvider.BindEnvVar("connectProtocol", "CELERITY_CLI_CONNECT_PROTOCOL")
 
-	rootCmd.PersistentFlags().String(
-		"engine-endpoint",
-		"http://localhost:8325",
-		"The endpoint of the deploy engine api, this is used if --connect-protocol is set to \"tcp\"",
+	// This can not be set in a config file as it would allow a project
+	// config file to opt in to running its own commands.
+	rootCmd.PersistentFlags().BoolVar(
+		&allowCmdReferences,
+		"allow-cmd-references",
+		false,
+		"Resolve \"${file:path}\" and \"${cmd:command}\" references in project config files, "+
+			"deploy config files and var files. "+
+			"By default, these references are only resolved from the user-level config file "+
+			"and the config file provided with --config as they can read any file or run any command "+
+			"when a config value is used. Only set this for projects that you trust.",
 	)
-	confProvider.BindPFlag("engineEndpoint", rootCmd.PersistentFlags().Lookup("engine-endpoint"))
-	confProvider.BindEnvVar("engineEndpoint", "CELERITY_CLI_ENGINE_ENDPOINT")
 
-	rootCmd.PersistentFlags().Bool(
-		"skip-plugin-config-validation",
-		false,
-		"Skip validation of the plugin-specific entries in the deploy configuration file for commands that interact with the deploy engine.",
+	confProvider.Register(configKeys()...)
+	confProvider.AddFlags(
+		rootCmd.PersistentFlags(),
+		config.ProfileConfigName,
+		"deployConfigFile",
+		"env",
+		"connectProtocol",
+		"engine.endpoint",
+		"engine.unixSocket",
+		"engine.authMethod",
+		"skipPluginConfigValidation",
 	)
-	confProvider.BindPFlag("skipPluginConfigValidation", rootCmd.PersistentFlags().Lookup("skip-plugin-config-validation"))
-	confProvider.BindEnvVar("skipPluginConfigValidation", "CELERITY_CLI_SKIP_PLUGIN_CONFIG_VALIDATION")
 
 	setupVersionCommand(rootCmd)
 	setupInitCommand(rootCmd, confProvider)
 	setupValidateCommand(rootCmd, confProvider)
+	setupStageCommand(rootCmd, confProvider)
+	setupDeployCommand(rootCmd, confProvider)
+	setupDestroyCommand(rootCmd, confProvider)
+	setupInstanceCommand(rootCmd, confProvider)
+	setupCleanupCommand(rootCmd, confProvider)
+	setupProfileCommand(rootCmd, confProvider)
+	setupConfigCommand(rootCmd, confProvider)
 
 	return rootCmd
 }
 
+// annotationSkipConfigChecks is set on commands that must work with
+// invalid config so they can be used to fix it, this applies to all
+// subcommands of a command with the annotation.
+const annotationSkipConfigChecks = "celerity_skip_config_checks"
+
+func skipsConfigChecks(cmd *cobra.Command) bool {
+	for current := cmd; current != nil; current = current.Parent() {
+		if current.Annotations[annotationSkipConfigChecks] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// annotationCreatesConfigFile is set on commands that create the config file
+// provided with --config so the config file does not need to exist.
+const annotationCreatesConfigFile = "celerity_creates_config_file"
+
+func createsConfigFile(cmd *cobra.Command) bool {
+	return cmd.Annotations[annotationCreatesConfigFile] == "true"
+}
+
 func validateConnectProtocol(protocol string) error {
 	if protocol == "tcp" {
 		return nil
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/stageui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func setupStageCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
+	stageCmd := &cobra.Command{
+		Use:   "stage",
+		Short: "Stages changes for a blueprint deployment",
+		Long: `Creates a change set for a Celerity blueprint and streams the changes
+	as they are computed by the deploy engine.
+	You can use this command to preview what a deployment will do
+	before running the deploy command.
+
+	If an instance ID or name is provided, changes will be staged against the current
+	state of the existing blueprint instance, otherwise changes will be staged for
+	a new blueprint instance.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
+			if err != nil {
+				return err
+			}
+			defer handle.Close()
+
+			deployEngine, err := engine.Create(confProvider, logger)
+			if err != nil {
+				return err
+			}
+
+			blueprintFile, _ := confProvider.GetString("stage.blueprintFile")
+			instanceID, _ := confProvider.GetString("stage.instanceID")
+			instanceName, _ := confProvider.GetString("stage.instanceName")
+			destroy, _ := confProvider.GetBool("stage.destroy")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			if _, err := blueprint.ParseLocation(blueprintFile); err != nil {
+				return err
+			}
+
+			deployConfig, err := loadDeployConfigWithVars(confProvider, "stage", logger)
+			if err != nil {
+				return err
+			}
+
+			opts := &stage.Options{
+				BlueprintFile: blueprintFile,
+				InstanceID:    instanceID,
+				InstanceName:  instanceName,
+				Destroy:       destroy,
+				DeployConfig:  deployConfig,
+				RemoteEngine:  engine.IsRemote(confProvider),
+			}
+
+			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+			if !inTerminal {
+				handler := handlers.NewStageHandler(
+					deployEngine,
+					opts,
+					os.Stdout,
+					logger,
+				)
+				return handler.Handle(context.TODO())
+			}
+
+			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
+				log.Fatal(err)
+			}
+
+			styles := styles.NewDefaultCelerityStyles()
+			app, err := stageui.NewStageApp(deployEngine, logger, opts, styles)
+			if err != nil {
+				return err
+			}
+			finalModel, err := tea.NewProgram(
+				app,
+				tea.WithOutput(confProvider.Redactor().File(os.Stdout)),
+			).Run()
+			if err != nil {
+				return err
+			}
+			finalApp := finalModel.(stageui.MainModel)
+
+			if finalApp.Error != nil {
+				return finalApp.Error
+			}
+
+			return nil
+		},
+	}
+
+	confProvider.AddFlags(
+		stageCmd.PersistentFlags(),
+		"stage.blueprintFile",
+		"stage.instanceID",
+		"stage.instanceName",
+		"stage.destroy",
+		"stage.vars",
+		"stage.varFiles",
+	)
+
+	rootCmd.AddCommand(stageCmd)
+}
@@ -2,31 +2,54 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
 	"github.com/newstack-cloud/celerity/apps/cli/cmd/utils"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/handlers"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/validateui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 func setupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider) {
 	validateCmd := &cobra.Command{
-		Use:   "validate",
+		Use:   "validate [blueprint files...]",
 		Short: "Validates a Celerity blueprint",
 		Long: `Carries out validation on a Celerity blueprint.
 	You can use this command to check for issues with a blueprint
 	before deployment.
 
-	It's worth noting that validation is carried out as a part of the deploy command as well.`,
+	Multiple blueprint files or glob patterns can be provided with --blueprint-file
+	or as arguments, for example, 'celerity validate "infra/**/*.blueprint.yaml"'.
+	Blueprint files are validated concurrently with up to --parallelism files at a time
+	and diagnostics are grouped by blueprint file followed by a summary.
+
+	It's worth noting that validation is carried out as a part of the deploy command as well.
+
+	With --watch, the blueprint is validated again each time the blueprint file,
+	its child blueprints or the deploy config files change, until the command is interrupted.
+
+	In a terminal, diagnostics can be browsed and filtered interactively,
+	press e, w or i to show or hide errors, warnings and info, o to open the focused
+	diagnostic in $EDITOR at the line it refers to and r to validate again.
+
+	Exit codes:
+	  0  validation completed without diagnostics at or above the --fail-on level
+	  1  the validation process could not be carried out
+	  2  validation produced diagnostics at or above the --fail-on level`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			logger, handle, err := utils.SetupLogger()
+			logger, handle, err := utils.SetupLogger(confProvider.Redactor())
 			if err != nil {
 				return err
 			}
@@ -36,13 +59,105 @@ func setupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider)
 			if err != nil {
 				return err
 			}
-			blueprintFile, isDefault := confProvider.GetString("validateBlueprintFile")
+
+			patterns, isDefault := confProvider.GetStringSlice("validate.blueprintFile")
+			parallelism, _ := confProvider.GetInt64("validate.parallelism")
+			formatValue, _ := confProvider.GetString("validate.format")
+			failOnValue, _ := confProvider.GetString("validate.failOn")
+			hyperlinksValue, _ := confProvider.GetString("validate.hyperlinks")
+			watchMode, _ := confProvider.GetBool("validate.watch")
+			skipPluginConfigValidation, _ := confProvider.GetBool("skipPluginConfigValidation")
+			if err := confProvider.ValueErrors(); err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				// Blueprint files provided as arguments replace the default
+				// blueprint file, this allows for file lists expanded by the shell.
+				if isDefault {
+					patterns = nil
+				}
+				patterns = append(patterns, args...)
+				isDefault = false
+			}
+			blueprintFiles, err := blueprint.ExpandPatterns(patterns)
+			if err != nil {
+				return err
+			}
+			if len(blueprintFiles) == 0 {
+				return errors.New("at least one blueprint file must be provided")
+			}
+
+			if parallelism < 1 {
+				return fmt.Errorf("--parallelism must be at least 1, found %d", parallelism)
+			}
+
+			format, err := validate.ParseFormat(formatValue)
+			if err != nil {
+				return err
+			}
+
+			failOn, err := validate.ParseFailOn(failOnValue)
+			if err != nil {
+				return err
+			}
 
 			inTerminal := term.IsTerminal(int(os.Stdout.Fd()))
-			if !inTerminal {
-				handler := handlers.NewValidateHandler(
+			hyperlinks, err := validate.ParseHyperlinks(hyperlinksValue, inTerminal)
+			if err != nil {
+				return err
+			}
+
+			if watchMode && format != validate.FormatText {
+				return fmt.Errorf("--watch can not be used with the %q format", format)
+			}
+			if watchMode && len(blueprintFiles) > 1 {
+				return errors.New("--watch can only be used with a single blueprint file")
+			}
+			if watchMode && isRemoteLocation(blueprintFiles[0]) {
+				return errors.New("--watch can not be used with a remote blueprint file")
+			}
+			// Usage is not relevant for failures that are reported
+			// after the flags have been successfully parsed.
+			cmd.SilenceUsage = true
+
+			deployConfig, err := loadDeployConfigWithVars(confProvider, "validate", logger)
+			if err != nil {
+				return err
+			}
+
+			opts := &validate.Options{
+				BlueprintFiles:    blueprintFiles,
+				Parallelism:       int(parallelism),
+				RemoteEngine:      engine.IsRemote(confProvider),
+				Format:            format,
+				FailOn:            failOn,
+				DeployConfig:      deployConfig,
+				CheckPluginConfig: !skipPluginConfigValidation,
+				Hyperlinks:        hyperlinks,
+				Watch:             watchMode,
+			}
+			opts.LoadDeployConfig = func() (*types.BlueprintOperationConfig, error) {
+				return loadDeployConfigWithVars(confProvider, "validate", logger)
+			}
+			if watchMode {
+				opts.WatchFiles = deployConfigWatchPaths(confProvider, "validate")
+			}
+
+			// Machine-readable formats are always written without the
+			// interactive UI so they can be redirected or piped to other tools.
+			if !inTerminal || format != validate.FormatText {
+				newHandler := handlers.NewValidateHandler
+				ctx := context.TODO()
+				if watchMode {
+					newHandler = handlers.NewValidateWatchHandler
+					var stop context.CancelFunc
+					ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+					defer stop()
+				}
+				handler := newHandler(
 					deployEngine,
-					blueprintFile,
+					opts,
 					// When not in a terminal, print output
 					// that is intended primarily for a human to read
 					// should always go to stdout for the process.
@@ -51,7 +166,7 @@ func setupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider)
 					// that is intended primarily for debugging.
 					logger,
 				)
-				return handler.Handle(context.TODO())
+				return handler.Handle(ctx)
 			}
 
 			if _, err := tea.LogToFile("celerity-output.log", "simple"); err != nil {
@@ -59,11 +174,15 @@ func setupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider)
 			}
 
 			styles := styles.NewDefaultCelerityStyles()
-			app, err := validateui.NewValidateApp(deployEngine, logger, blueprintFile, isDefault, styles)
+			app, err := validateui.NewValidateApp(deployEngine, logger, opts, isDefault, styles)
 			if err != nil {
 				return err
 			}
-			finalModel, err := tea.NewProgram(app).Run()
+			defer app.Close()
+			finalModel, err := tea.NewProgram(
+				app,
+				tea.WithOutput(confProvider.Redactor().File(os.Stdout)),
+			).Run()
 			if err != nil {
 				return err
 			}
@@ -77,14 +196,22 @@ func setupValidateCommand(rootCmd *cobra.Command, confProvider *config.Provider)
 		},
 	}
 
-	validateCmd.PersistentFlags().StringP(
-		"blueprint-file",
-		"b",
-		"app.blueprint.yaml",
-		"The blueprint file to use in the validation process.",
+	confProvider.AddFlags(
+		validateCmd.PersistentFlags(),
+		"validate.blueprintFile",
+		"validate.parallelism",
+		"validate.hyperlinks",
+		"validate.format",
+		"validate.failOn",
+		"validate.vars",
+		"validate.varFiles",
+		"validate.watch",
 	)
-	confProvider.BindPFlag("validateBlueprintFile", validateCmd.PersistentFlags().Lookup("blueprint-file"))
-	confProvider.BindEnvVar("validateBlueprintFile", "CELERITY_CLI_VALIDATE_BLUEPRINT_FILE")
 
 	rootCmd.AddCommand(validateCmd)
 }
+
+func isRemoteLocation(value string) bool {
+	location, err := blueprint.ParseLocation(value)
+	return err == nil && location.IsRemote()
+}
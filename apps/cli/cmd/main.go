@@ -1,12 +1,21 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"github.com/newstack-cloud/celerity/apps/cli/cmd/commands"
 	"github.com/spf13/cobra"
 )
 
+// exitCoder is implemented by errors that should cause the CLI
+// to exit with a specific exit code, such as validation diagnostics
+// at or above the --fail-on level.
+type exitCoder interface {
+	ExitCode() int
+}
+
 func init() {
 	cobra.OnInitialize(commands.OnInitialise)
 }
@@ -14,6 +23,11 @@ func init() {
 func main() {
 	rootCmd := commands.NewRootCmd()
 	if err := rootCmd.Execute(); err != nil {
+		var withExitCode exitCoder
+		if errors.As(err, &withExitCode) {
+			log.Print(err)
+			os.Exit(withExitCode.ExitCode())
+		}
 		log.Fatal(err)
 	}
 }
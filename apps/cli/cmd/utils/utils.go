@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -58,7 +59,9 @@ var HelpTemplate = `
 
 // SetupLogger creates a zap logger instance that writes to a file.
 // Due to the CLI heavily using bubbletea to provide interactive experiences,
-func SetupLogger() (*zap.Logger, *os.File, error) {
+// logs are written to a file as stdout is used to render the terminal UI.
+// Secrets tracked by the provided redactor are removed from log entries.
+func SetupLogger(redactor *config.Redactor) (*zap.Logger, *os.File, error) {
 	logFileHandle, err := os.OpenFile("celerity.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, nil, err
@@ -70,7 +73,7 @@ func SetupLogger() (*zap.Logger, *os.File, error) {
 		// stdout and stdin are used for communication with the client
 		// and should not be logged to.
 		// zapcore.AddSync(os.Stderr),
-		zapcore.AddSync(logFileHandle),
+		zapcore.AddSync(redactor.Writer(logFileHandle)),
 	)
 	core := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(cfg),
@@ -9,6 +9,7 @@ require (
 	github.com/charmbracelet/bubbles v0.21.0
 	github.com/charmbracelet/bubbletea v1.3.5
 	github.com/charmbracelet/lipgloss v1.1.0
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/newstack-cloud/bluelink/libs/blueprint v0.24.1
 	github.com/newstack-cloud/bluelink/libs/blueprint-state v0.2.6
 	github.com/newstack-cloud/bluelink/libs/common v0.3.2
@@ -0,0 +1,73 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+)
+
+var (
+	// ErrLocalFileWithRemoteEngine is returned when a blueprint file on the local
+	// file system is used with a deploy engine that runs on a different machine.
+	// The deploy engine API only accepts locations of blueprint documents,
+	// there is no field for sending the content of a blueprint document inline
+	// in the deploy engine client, so the file must be made available from
+	// a remote source that the deploy engine can read from.
+	ErrLocalFileWithRemoteEngine = errors.New(
+		"the deploy engine is remote and can not read blueprint files from this machine, " +
+			"use a blueprint file from a remote source (s3, gcs, azureblob or https) " +
+			"or connect to a deploy engine running on this machine",
+	)
+)
+
+// DocumentInfoFromPath creates the blueprint document information
+// that is sent to the deploy engine for a blueprint file on the local file system.
+// The deploy engine expects an absolute directory for the "file" source scheme,
+// so relative paths are resolved against the current working directory.
+func DocumentInfoFromPath(blueprintFile string) (types.BlueprintDocumentInfo, error) {
+	absPath, err := filepath.Abs(blueprintFile)
+	if err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	return types.BlueprintDocumentInfo{
+		FileSourceScheme: consts.BlueprintSourceFile,
+		Directory:        filepath.Dir(absPath),
+		BlueprintFile:    filepath.Base(absPath),
+	}, nil
+}
+
+// ResolveLocalDocumentInfo creates the blueprint document information for a
+// blueprint file on the local file system, making sure the file exists
+// and that the deploy engine is able to read it.
+func ResolveLocalDocumentInfo(
+	blueprintFile string,
+	remoteEngine bool,
+) (types.BlueprintDocumentInfo, error) {
+	if remoteEngine {
+		return types.BlueprintDocumentInfo{}, ErrLocalFileWithRemoteEngine
+	}
+
+	docInfo, err := DocumentInfoFromPath(blueprintFile)
+	if err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	fileInfo, err := os.Stat(filepath.Join(docInfo.Directory, docInfo.BlueprintFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return types.BlueprintDocumentInfo{}, fmt.Errorf("blueprint file %q does not exist", blueprintFile)
+		}
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	if fileInfo.IsDir() {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf("blueprint file %q is a directory", blueprintFile)
+	}
+
+	return docInfo, nil
+}
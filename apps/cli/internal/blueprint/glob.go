@@ -0,0 +1,165 @@
+package blueprint
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExpandPatterns expands the provided blueprint locations into a list of
+// blueprint files, where local paths can be glob patterns.
+// Glob patterns support the syntax of path.Match for each path segment along
+// with "**" to match any number of directories, for example,
+// "infra/**/*.blueprint.yaml".
+// Locations that are not glob patterns are returned as they are so they can
+// be checked when they are validated and duplicates are removed while
+// preserving the order in which locations are provided.
+func ExpandPatterns(patterns []string) ([]string, error) {
+	files := []string{}
+	seen := map[string]bool{}
+	addFile := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, pattern := range patterns {
+		location, err := ParseLocation(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if !IsGlobPattern(location.Path) {
+			addFile(pattern)
+			continue
+		}
+
+		if location.IsRemote() {
+			return nil, fmt.Errorf(
+				"glob patterns can only be used for local blueprint files, found %q",
+				pattern,
+			)
+		}
+
+		matches, err := Glob(location.Path)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no blueprint files match %q", pattern)
+		}
+		for _, match := range matches {
+			addFile(match)
+		}
+	}
+
+	return files, nil
+}
+
+// IsGlobPattern determines whether a path contains
+// any special characters used in glob patterns.
+func IsGlobPattern(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+// Glob returns the sorted paths of the files on the local file system
+// that match the provided pattern.
+// Unlike filepath.Glob, "**" matches any number of directories,
+// directories with names that start with a "." are not matched by "**"
+// in the same way as globstar in most shells.
+func Glob(pattern string) ([]string, error) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+
+	// Only the directory before the first segment that contains a glob
+	// pattern needs to be walked.
+	baseEnd := slices.IndexFunc(segments, IsGlobPattern)
+	baseDir := filepath.FromSlash(strings.Join(segments[:baseEnd], "/"))
+	if baseDir == "" && baseEnd > 0 {
+		// The pattern is an absolute path in the root directory.
+		baseDir = string(filepath.Separator)
+	}
+	walkDir := baseDir
+	if walkDir == "" {
+		walkDir = "."
+	}
+	patternSegments := segments[baseEnd:]
+
+	matches := []string{}
+	err := filepath.WalkDir(walkDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Directories that can not be read are skipped,
+			// a base directory that does not exist has no matches.
+			if entry != nil && entry.IsDir() && filePath != walkDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if filePath == walkDir {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(walkDir, filePath)
+		if err != nil {
+			return err
+		}
+		relSegments := strings.Split(filepath.ToSlash(relPath), "/")
+
+		if entry.IsDir() {
+			if !matchSegments(patternSegments, relSegments, true) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if matchSegments(patternSegments, relSegments, false) {
+			matches = append(matches, filepath.Join(baseDir, relPath))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// matchSegments matches path segments against glob pattern segments,
+// when prefix is true, this determines whether the segments are the
+// directories of a path that could match the pattern.
+func matchSegments(pattern []string, segments []string, prefix bool) bool {
+	if len(segments) == 0 {
+		if prefix {
+			return true
+		}
+		return !slices.ContainsFunc(pattern, func(segment string) bool {
+			return segment != "**"
+		})
+	}
+
+	if len(pattern) == 0 {
+		return false
+	}
+
+	if pattern[0] == "**" {
+		if matchSegments(pattern[1:], segments, prefix) {
+			return true
+		}
+		if strings.HasPrefix(segments[0], ".") {
+			return false
+		}
+		return matchSegments(pattern, segments[1:], prefix)
+	}
+
+	matched, _ := path.Match(pattern[0], segments[0])
+	return matched && matchSegments(pattern[1:], segments[1:], prefix)
+}
@@ -0,0 +1,248 @@
+package blueprint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := createTestTree(t,
+		"root.blueprint.yaml",
+		"infra/app.blueprint.yaml",
+		"infra/app.blueprint.json",
+		"infra/README.md",
+		"infra/services/api/api.blueprint.yaml",
+		"infra/services/jobs/nested/jobs.blueprint.yaml",
+		"infra/services/.hidden/secret.blueprint.yaml",
+	)
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "double star matches any number of directories",
+			pattern: "infra/**/*.blueprint.yaml",
+			want: []string{
+				"infra/app.blueprint.yaml",
+				"infra/services/api/api.blueprint.yaml",
+				"infra/services/jobs/nested/jobs.blueprint.yaml",
+			},
+		},
+		{
+			name:    "double star at the start of the pattern",
+			pattern: "**/*.blueprint.yaml",
+			want: []string{
+				"infra/app.blueprint.yaml",
+				"infra/services/api/api.blueprint.yaml",
+				"infra/services/jobs/nested/jobs.blueprint.yaml",
+				"root.blueprint.yaml",
+			},
+		},
+		{
+			name:    "double star between directories",
+			pattern: "infra/**/nested/*.yaml",
+			want:    []string{"infra/services/jobs/nested/jobs.blueprint.yaml"},
+		},
+		{
+			name:    "single star matches one directory including hidden directories",
+			pattern: "infra/*/*/*.blueprint.yaml",
+			want: []string{
+				"infra/services/.hidden/secret.blueprint.yaml",
+				"infra/services/api/api.blueprint.yaml",
+			},
+		},
+		{
+			name:    "hidden directories can be matched explicitly",
+			pattern: "infra/**/.hidden/*.yaml",
+			want:    []string{"infra/services/.hidden/secret.blueprint.yaml"},
+		},
+		{
+			name:    "character classes",
+			pattern: "infra/app.blueprint.[jy]*",
+			want: []string{
+				"infra/app.blueprint.json",
+				"infra/app.blueprint.yaml",
+			},
+		},
+		{
+			name:    "no matches",
+			pattern: "infra/**/*.blueprint.toml",
+			want:    []string{},
+		},
+		{
+			name:    "base directory that does not exist",
+			pattern: "missing/**/*.blueprint.yaml",
+			want:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches, err := Glob(filepath.Join(dir, filepath.FromSlash(test.pattern)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := testTreePaths(dir, test.want)
+			if !slices.Equal(matches, want) {
+				t.Errorf("unexpected matches\nwant: %v\ngot:  %v", want, matches)
+			}
+		})
+	}
+}
+
+func TestGlobInvalidPattern(t *testing.T) {
+	_, err := Glob("infra/[/*.blueprint.yaml")
+	if err == nil || !strings.Contains(err.Error(), "invalid glob pattern") {
+		t.Fatalf("expected an invalid glob pattern error, got %v", err)
+	}
+}
+
+func TestExpandPatterns(t *testing.T) {
+	dir := createTestTree(t,
+		"infra/app.blueprint.yaml",
+		"infra/services/api.blueprint.yaml",
+		"infra/services/jobs.blueprint.yaml",
+	)
+	path := func(name string) string {
+		return filepath.Join(dir, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name: "duplicate matches are removed in the order they are found",
+			patterns: []string{
+				path("infra/services/jobs.blueprint.yaml"),
+				path("infra/**/*.blueprint.yaml"),
+				path("infra/services/*.blueprint.yaml"),
+			},
+			want: []string{
+				path("infra/services/jobs.blueprint.yaml"),
+				path("infra/app.blueprint.yaml"),
+				path("infra/services/api.blueprint.yaml"),
+			},
+		},
+		{
+			name: "duplicate locations that are not patterns are removed",
+			patterns: []string{
+				path("infra/app.blueprint.yaml"),
+				"s3://bucket/app.blueprint.yaml",
+				path("infra/app.blueprint.yaml"),
+				"s3://bucket/app.blueprint.yaml",
+			},
+			want: []string{
+				path("infra/app.blueprint.yaml"),
+				"s3://bucket/app.blueprint.yaml",
+			},
+		},
+		{
+			name:     "locations that are not patterns are not checked",
+			patterns: []string{path("missing.blueprint.yaml")},
+			want:     []string{path("missing.blueprint.yaml")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := ExpandPatterns(test.patterns)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(files, test.want) {
+				t.Errorf("unexpected files\nwant: %v\ngot:  %v", test.want, files)
+			}
+		})
+	}
+}
+
+func TestExpandPatternsErrors(t *testing.T) {
+	dir := createTestTree(t, "infra/app.blueprint.yaml")
+
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  string
+	}{
+		{
+			name: "pattern without matches",
+			patterns: []string{
+				filepath.Join(dir, "infra", "app.blueprint.yaml"),
+				filepath.Join(dir, "**", "*.blueprint.json"),
+			},
+			wantErr: "no blueprint files match",
+		},
+		{
+			name:     "pattern for a remote location",
+			patterns: []string{"s3://bucket/infra/*.blueprint.yaml"},
+			wantErr:  "glob patterns can only be used for local blueprint files",
+		},
+		{
+			name:     "unsupported source",
+			patterns: []string{"ftp://example.com/app.blueprint.yaml"},
+			wantErr:  "unsupported blueprint source \"ftp\"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ExpandPatterns(test.patterns)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIsGlobPattern(t *testing.T) {
+	tests := map[string]bool{
+		"app.blueprint.yaml":         false,
+		"infra/**/*.blueprint.yaml":  true,
+		"app.blueprint.y?ml":         true,
+		"app.blueprint.[jy]*":        true,
+		"s3://bucket/app.blueprint":  false,
+		"infra/services/app.ya-ml":   false,
+		"infra/{app,jobs}.blueprint": false,
+	}
+
+	for value, want := range tests {
+		if got := IsGlobPattern(value); got != want {
+			t.Errorf("IsGlobPattern(%q) = %t, expected %t", value, got, want)
+		}
+	}
+}
+
+// createTestTree creates empty files at the provided slash-separated
+// paths in a temporary directory and returns the directory.
+func createTestTree(t *testing.T, files ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", file, err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", file, err)
+		}
+	}
+	return dir
+}
+
+func testTreePaths(dir string, files []string) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, filepath.Join(dir, filepath.FromSlash(file)))
+	}
+	return paths
+}
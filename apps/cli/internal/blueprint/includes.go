@@ -0,0 +1,102 @@
+package blueprint
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/schema"
+)
+
+// LocalIncludes finds the child blueprint files on the local file system
+// that are included by the provided blueprint file, including the child
+// blueprints of child blueprints.
+// Includes with a path that contains substitutions or a URI for
+// a remote location are skipped as they can only be resolved
+// by the deploy engine.
+// Relative include paths are resolved from the directory of the
+// blueprint file that includes them.
+func LocalIncludes(blueprintFile string) ([]string, error) {
+	includes := []string{}
+	visited := map[string]bool{}
+	err := collectLocalIncludes(blueprintFile, visited, &includes)
+	return includes, err
+}
+
+func collectLocalIncludes(blueprintFile string, visited map[string]bool, includes *[]string) error {
+	absPath, err := filepath.Abs(blueprintFile)
+	if err != nil {
+		return err
+	}
+
+	// Blueprints can include each other, so each blueprint file
+	// is only loaded once.
+	if visited[absPath] {
+		return nil
+	}
+	visited[absPath] = true
+
+	blueprint, err := schema.Load(absPath, specFormat(absPath))
+	if err != nil {
+		return err
+	}
+
+	if blueprint.Include == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(blueprint.Include.Values))
+	for name := range blueprint.Include.Values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		includePath, isLocal := localIncludePath(blueprint.Include.Values[name])
+		if !isLocal {
+			continue
+		}
+
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(absPath), includePath)
+		}
+		*includes = append(*includes, includePath)
+
+		// A child blueprint that can not be loaded will be reported
+		// by the deploy engine, it is still included in the list
+		// so changes to it can be picked up.
+		_ = collectLocalIncludes(includePath, visited, includes)
+	}
+
+	return nil
+}
+
+func localIncludePath(include *schema.Include) (string, bool) {
+	if include == nil || include.Path == nil {
+		return "", false
+	}
+
+	sb := strings.Builder{}
+	for _, value := range include.Path.Values {
+		if value == nil || value.StringValue == nil {
+			return "", false
+		}
+		sb.WriteString(*value.StringValue)
+	}
+
+	path := sb.String()
+	if path == "" || strings.Contains(path, "://") {
+		return "", false
+	}
+
+	return path, true
+}
+
+func specFormat(blueprintFile string) schema.SpecFormat {
+	switch filepath.Ext(blueprintFile) {
+	case ".json", ".jsonc":
+		return schema.JWCCSpecFormat
+	}
+
+	return schema.YAMLSpecFormat
+}
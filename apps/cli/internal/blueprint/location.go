@@ -0,0 +1,96 @@
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+)
+
+var (
+	// SupportedSources is a list of all the blueprint file source schemes
+	// that can be used in a blueprint location.
+	SupportedSources = []string{
+		consts.BlueprintSourceFile,
+		consts.BlueprintSourceS3,
+		consts.BlueprintSourceGCS,
+		consts.BlueprintSourceAzureBlob,
+		consts.BlueprintSourceHTTPS,
+	}
+)
+
+// Location is the parsed form of a blueprint location provided by the user.
+type Location struct {
+	// Scheme is the file source scheme for the blueprint document,
+	// this will be "file" for local paths.
+	Scheme string
+	// Path is the location of the blueprint document relative to the scheme.
+	// For local files this is the file path, for object storage sources this
+	// is in the form "{bucket}/{path/to/blueprint.yaml}" and for https sources
+	// this is the URL excluding the scheme.
+	Path string
+}
+
+// IsRemote determines whether the location refers to a blueprint
+// document that is not on the local file system.
+func (l *Location) IsRemote() bool {
+	return IsRemoteSource(l.Scheme)
+}
+
+// ParseLocation parses a blueprint location that can either be a local file path
+// or a URI with a supported source scheme.
+// For example, "app.blueprint.yaml", "file:///path/to/app.blueprint.yaml",
+// "s3://bucket/path/to/app.blueprint.yaml", "gcs://bucket/app.blueprint.yaml",
+// "azureblob://container/app.blueprint.yaml" or "https://example.com/app.blueprint.yaml".
+// The format of remote locations is validated so errors are reported
+// before any requests are made to the deploy engine.
+func ParseLocation(value string) (*Location, error) {
+	scheme, path, hasScheme := strings.Cut(value, "://")
+	if !hasScheme {
+		return &Location{
+			Scheme: consts.BlueprintSourceFile,
+			Path:   value,
+		}, nil
+	}
+
+	if !slices.Contains(SupportedSources, scheme) {
+		return nil, fmt.Errorf(
+			"unsupported blueprint source %q in %q, must be one of %s",
+			scheme,
+			value,
+			strings.Join(SupportedSources, ", "),
+		)
+	}
+
+	location := &Location{
+		Scheme: scheme,
+		Path:   path,
+	}
+	if location.IsRemote() {
+		if _, err := RemoteDocumentInfo(scheme, path); err != nil {
+			return nil, err
+		}
+	} else if path == "" {
+		return nil, fmt.Errorf("a file path must be provided in %q", value)
+	}
+
+	return location, nil
+}
+
+// ResolveDocumentInfo creates the blueprint document information for the
+// provided blueprint location, making sure that local blueprint files
+// exist and can be read by the deploy engine.
+func ResolveDocumentInfo(value string, remoteEngine bool) (types.BlueprintDocumentInfo, error) {
+	location, err := ParseLocation(value)
+	if err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	if location.IsRemote() {
+		return RemoteDocumentInfo(location.Scheme, location.Path)
+	}
+
+	return ResolveLocalDocumentInfo(location.Path, remoteEngine)
+}
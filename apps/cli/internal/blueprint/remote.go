@@ -0,0 +1,156 @@
+package blueprint
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+)
+
+var (
+	// SupportedFileExtensions is a list of file extensions
+	// for the blueprint document formats supported by the deploy engine.
+	SupportedFileExtensions = []string{".yaml", ".yml", ".json"}
+
+	// See: https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucketnamingrules.html
+	s3BucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	// See: https://cloud.google.com/storage/docs/buckets#naming
+	gcsBucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+	// See: https://learn.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-containers--blobs--and-metadata
+	azureContainerPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+)
+
+// IsRemoteSource determines whether the provided file source scheme
+// refers to a blueprint document that is not on the local file system.
+func IsRemoteSource(scheme string) bool {
+	return scheme != "" && scheme != consts.BlueprintSourceFile
+}
+
+// RemoteDocumentInfo creates the blueprint document information for a blueprint
+// stored in a remote source.
+// For object storage sources (s3, gcs and azureblob), the location must be
+// in the form "{bucket}/{path/to/blueprint.yaml}".
+// For https sources, the location must be a URL, the "https://" prefix is optional.
+func RemoteDocumentInfo(scheme string, location string) (types.BlueprintDocumentInfo, error) {
+	trimmed := strings.TrimSpace(location)
+	if trimmed == "" {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf("a blueprint location must be provided")
+	}
+
+	switch scheme {
+	case consts.BlueprintSourceS3:
+		return objectStorageDocumentInfo(scheme, trimmed, "bucket", s3BucketPattern)
+	case consts.BlueprintSourceGCS:
+		return objectStorageDocumentInfo(scheme, trimmed, "bucket", gcsBucketPattern)
+	case consts.BlueprintSourceAzureBlob:
+		if strings.Contains(strings.SplitN(trimmed, "/", 2)[0], "--") {
+			return types.BlueprintDocumentInfo{}, fmt.Errorf(
+				"invalid container name in %q, container names can not contain consecutive hyphens",
+				trimmed,
+			)
+		}
+		return objectStorageDocumentInfo(scheme, trimmed, "container", azureContainerPattern)
+	case consts.BlueprintSourceHTTPS:
+		return httpsDocumentInfo(trimmed)
+	}
+
+	return types.BlueprintDocumentInfo{}, fmt.Errorf("unsupported blueprint source %q", scheme)
+}
+
+func objectStorageDocumentInfo(
+	scheme string,
+	location string,
+	bucketLabel string,
+	bucketPattern *regexp.Regexp,
+) (types.BlueprintDocumentInfo, error) {
+	bucket, objectPath, hasObjectPath := strings.Cut(strings.TrimPrefix(location, "/"), "/")
+	if !bucketPattern.MatchString(bucket) {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf(
+			"invalid %s name %q for a %s source",
+			bucketLabel,
+			bucket,
+			scheme,
+		)
+	}
+
+	if !hasObjectPath || objectPath == "" {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf(
+			"a path to the blueprint file in the %s must be provided after the %s name, "+
+				"for example \"%s/path/to/app.blueprint.yaml\"",
+			bucketLabel,
+			bucketLabel,
+			bucket,
+		)
+	}
+
+	fileName := path.Base(objectPath)
+	if err := checkFileExtension(fileName); err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	return types.BlueprintDocumentInfo{
+		FileSourceScheme: scheme,
+		Directory:        path.Join(bucket, path.Dir(objectPath)),
+		BlueprintFile:    fileName,
+	}, nil
+}
+
+func httpsDocumentInfo(location string) (types.BlueprintDocumentInfo, error) {
+	withScheme := location
+	if !strings.Contains(location, "://") {
+		withScheme = "https://" + location
+	}
+
+	parsedURL, err := url.Parse(withScheme)
+	if err != nil {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf("invalid blueprint URL %q: %w", location, err)
+	}
+
+	if parsedURL.Scheme != "https" {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf(
+			"invalid blueprint URL %q, only https URLs are supported",
+			location,
+		)
+	}
+
+	if parsedURL.Host == "" {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf("invalid blueprint URL %q, a host must be provided", location)
+	}
+
+	if parsedURL.RawQuery != "" || parsedURL.Fragment != "" {
+		return types.BlueprintDocumentInfo{}, fmt.Errorf(
+			"invalid blueprint URL %q, query strings and fragments are not supported",
+			location,
+		)
+	}
+
+	fileName := path.Base(parsedURL.Path)
+	if err := checkFileExtension(fileName); err != nil {
+		return types.BlueprintDocumentInfo{}, err
+	}
+
+	return types.BlueprintDocumentInfo{
+		FileSourceScheme: consts.BlueprintSourceHTTPS,
+		// The deploy engine expects the base URL of the blueprint
+		// document excluding the scheme.
+		Directory:     strings.TrimSuffix(parsedURL.Host+path.Dir(parsedURL.Path), "/"),
+		BlueprintFile: fileName,
+	}, nil
+}
+
+func checkFileExtension(fileName string) error {
+	if slices.Contains(SupportedFileExtensions, path.Ext(fileName)) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%q is not a supported blueprint file, must have one of the extensions %s",
+		fileName,
+		strings.Join(SupportedFileExtensions, ", "),
+	)
+}
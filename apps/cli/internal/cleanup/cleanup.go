@@ -0,0 +1,75 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+)
+
+// Target is a type of data stored by the deploy engine
+// that can be cleaned up when it is older than the retention
+// period configured for the deploy engine instance.
+type Target string
+
+const (
+	// TargetValidations is used to clean up blueprint validations.
+	TargetValidations Target = "validations"
+	// TargetChangesets is used to clean up change sets.
+	TargetChangesets Target = "change sets"
+	// TargetEvents is used to clean up events for all processes.
+	TargetEvents Target = "events"
+)
+
+var (
+	// ErrNoTargetsSelected is returned when a cleanup is requested
+	// without selecting anything to clean up.
+	ErrNoTargetsSelected = errors.New(
+		"nothing selected to clean up, use one or more of --validations, --changesets, --events or --all",
+	)
+)
+
+// Options holds the selection of what should be cleaned up
+// in the deploy engine.
+type Options struct {
+	Validations bool
+	Changesets  bool
+	Events      bool
+	All         bool
+}
+
+// Targets returns the list of targets selected in the provided options
+// in the order in which they are cleaned up.
+func Targets(opts *Options) ([]Target, error) {
+	targets := []Target{}
+	if opts.All || opts.Validations {
+		targets = append(targets, TargetValidations)
+	}
+
+	if opts.All || opts.Changesets {
+		targets = append(targets, TargetChangesets)
+	}
+
+	if opts.All || opts.Events {
+		targets = append(targets, TargetEvents)
+	}
+
+	if len(targets) == 0 {
+		return nil, ErrNoTargetsSelected
+	}
+
+	return targets, nil
+}
+
+// Trigger triggers the cleanup process for the provided target
+// in the deploy engine.
+func Trigger(ctx context.Context, deployEngine engine.DeployEngine, target Target) error {
+	switch target {
+	case TargetValidations:
+		return deployEngine.CleanupBlueprintValidations(ctx)
+	case TargetChangesets:
+		return deployEngine.CleanupChangesets(ctx)
+	default:
+		return deployEngine.CleanupEvents(ctx)
+	}
+}
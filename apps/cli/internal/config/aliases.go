@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Warnings returns warnings about the loaded config files
+// such as the use of deprecated config value names.
+func (p *Provider) Warnings() []string {
+	return p.warnings
+}
+
+// resolveAliases moves values set with the deprecated names of config values
+// in a config file to the current names of the config values,
+// a warning is recorded for each deprecated name that is used.
+func (p *Provider) resolveAliases(layer *configLayer) {
+	context := fmt.Sprintf("config file %q", layer.path)
+	p.resolveSectionAliases(context, layer.config)
+
+	profiles, _ := toMap(layer.config[profilesSection])
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		if profile, isSection := toMap(profiles[name]); isSection {
+			p.resolveSectionAliases(fmt.Sprintf("%s: profile %q", context, name), profile)
+			profiles[name] = profile
+		}
+	}
+}
+
+func (p *Provider) resolveSectionAliases(context string, section map[string]any) {
+	for _, key := range p.Keys() {
+		for _, alias := range key.Aliases {
+			value, hasAlias := section[alias]
+			if !hasAlias {
+				continue
+			}
+
+			delete(section, alias)
+			if _, hasValue := lookupValue(section, key.Name); hasValue {
+				p.warnings = append(p.warnings, fmt.Sprintf(
+					"%s: %q is deprecated and has been ignored as %q is also set",
+					context,
+					alias,
+					key.Name,
+				))
+				continue
+			}
+
+			mergeConfig(section, map[string]any{key.Name: value})
+			p.warnings = append(p.warnings, fmt.Sprintf(
+				"%s: %q is deprecated, use %q instead",
+				context,
+				alias,
+				key.Name,
+			))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// CheckConfig makes sure that the loaded config files only contain
+// registered config values with values of the registered types and that
+// environment variables for registered config values can be parsed.
+// Values that contain references are checked when they are looked up
+// as the references are resolved lazily.
+func (p *Provider) CheckConfig() error {
+	errs := []error{}
+	for _, layer := range p.configLayers {
+		context := fmt.Sprintf("config file %q", layer.path)
+		errs = append(errs, p.checkSection(context, "", layer.config, false)...)
+	}
+
+	for _, key := range p.Keys() {
+		if key.EnvVar == "" {
+			continue
+		}
+
+		envVarValue, hasEnvVar := os.LookupEnv(key.EnvVar)
+		if !hasEnvVar || strings.TrimSpace(envVarValue) == "" {
+			continue
+		}
+
+		if err := checkValue(key, envVarValue); err != nil {
+			errs = append(errs, fmt.Errorf("environment variable %s: %w", key.EnvVar, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Provider) checkSection(
+	context string,
+	prefix string,
+	section map[string]any,
+	inProfile bool,
+) []error {
+	errs := []error{}
+	for _, name := range slices.Sorted(maps.Keys(section)) {
+		value := section[name]
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if name == profilesSection && !inProfile {
+			errs = append(errs, p.checkProfiles(context, value)...)
+			continue
+		}
+
+		if name == ProfileConfigName && inProfile {
+			errs = append(errs, fmt.Errorf("%s: the active profile can not be set in a profile", context))
+			continue
+		}
+
+		if key, hasKey := p.keys[name]; hasKey {
+			if err := checkValue(key, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid value for %q: %w", context, name, err))
+			}
+			continue
+		}
+
+		if nested, isSection := toMap(value); isSection && p.hasKeysInSection(name) {
+			errs = append(errs, p.checkSection(context, name, nested, inProfile)...)
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("%s: unknown config value %q", context, name))
+	}
+
+	return errs
+}
+
+func (p *Provider) checkProfiles(context string, value any) []error {
+	profiles, isSection := toMap(value)
+	if !isSection {
+		return []error{fmt.Errorf("%s: %q must be a section of named profiles", context, profilesSection)}
+	}
+
+	errs := []error{}
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		profileContext := fmt.Sprintf("%s: profile %q", context, name)
+		profile, isSection := toMap(profiles[name])
+		if !isSection {
+			errs = append(errs, fmt.Errorf("%s: a profile must be a section of config values", profileContext))
+			continue
+		}
+
+		errs = append(errs, p.checkSection(profileContext, "", profile, true)...)
+	}
+
+	return errs
+}
+
+func (p *Provider) hasKeysInSection(section string) bool {
+	for name := range p.keys {
+		if strings.HasPrefix(name, section+".") {
+			return true
+		}
+	}
+	return false
+}
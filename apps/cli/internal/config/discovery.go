@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+var (
+	// ConfigFileNames are the names of the config files that are discovered
+	// in each directory between the working directory and the project root,
+	// in order of preference when a directory contains more than one of them.
+	ConfigFileNames = []string{
+		"celerity.config.toml",
+		"celerity.config.yaml",
+		"celerity.config.yml",
+		"celerity.config.json",
+	}
+
+	// ProjectRootMarkers are the files or directories that mark the root
+	// of a project, config file discovery does not go beyond the project root.
+	ProjectRootMarkers = []string{".git"}
+)
+
+// DiscoverProjectConfigFiles walks up from the provided working directory
+// to the project root and returns the paths of the config files that exist
+// along the way.
+// Paths are ordered from the project root to the working directory,
+// config files closer to the working directory take precedence
+// when the config files are merged.
+// When the working directory is not in a project, every directory
+// up to the root of the file system is searched.
+func DiscoverProjectConfigFiles(workingDir string) ([]string, error) {
+	currentDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	configFiles := []string{}
+	for {
+		configFile, hasConfigFile, err := findConfigFile(currentDir)
+		if err != nil {
+			return nil, err
+		}
+		if hasConfigFile {
+			configFiles = append(configFiles, configFile)
+		}
+
+		isProjectRoot, err := isProjectRoot(currentDir)
+		if err != nil {
+			return nil, err
+		}
+
+		parentDir := filepath.Dir(currentDir)
+		if isProjectRoot || parentDir == currentDir {
+			break
+		}
+		currentDir = parentDir
+	}
+
+	slices.Reverse(configFiles)
+	return configFiles, nil
+}
+
+// UserConfigDir returns the directory that holds the user-level
+// config file, this is "$XDG_CONFIG_HOME/celerity" if XDG_CONFIG_HOME is set,
+// otherwise the operating system specific user config directory is used
+// (e.g. "~/.config/celerity" on linux).
+func UserConfigDir() (string, error) {
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+		return filepath.Join(xdgConfigHome, "celerity"), nil
+	}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userConfigDir, "celerity"), nil
+}
+
+// UserConfigFilePath returns the path of the user-level config file,
+// when none of the supported config files exist in the user config
+// directory, the path of a TOML config file is returned.
+func UserConfigFilePath() (string, error) {
+	userConfigDir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	configFile, hasConfigFile, err := findConfigFile(userConfigDir)
+	if err != nil {
+		return "", err
+	}
+
+	if !hasConfigFile {
+		return filepath.Join(userConfigDir, ConfigFileNames[0]), nil
+	}
+
+	return configFile, nil
+}
+
+func findConfigFile(dir string) (string, bool, error) {
+	for _, fileName := range ConfigFileNames {
+		configFile := filepath.Join(dir, fileName)
+		exists, err := pathExists(configFile)
+		if err != nil {
+			return "", false, err
+		}
+		if exists {
+			return configFile, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func isProjectRoot(dir string) (bool, error) {
+	for _, marker := range ProjectRootMarkers {
+		exists, err := pathExists(filepath.Join(dir, marker))
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+
+	return false, nil
+}
+
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
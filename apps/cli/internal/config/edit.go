@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SetFileValue sets a config value in the config file at the provided path,
+// the config file will be created if it does not exist.
+// The config name can be prefixed with "profiles.<profile>." to set a value
+// in a profile.
+// Comments and the order of existing values are preserved for YAML and TOML
+// files and the order of existing values is preserved for JSON files.
+func SetFileValue(configFilePath string, configName string, value any) error {
+	data, err := readRawConfigFile(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	format, err := FileFormatFromPath(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	segments := strings.Split(configName, ".")
+	var updated []byte
+	switch format {
+	case FileFormatYAML:
+		updated, err = setYAMLValue(data, segments, value)
+	case FileFormatJSON:
+		updated, err = setJSONValue(data, segments, value)
+	case FileFormatTOML:
+		updated, err = setTOMLValue(data, segments, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set %q in config file %q: %w", configName, configFilePath, err)
+	}
+
+	return writeRawConfigFile(configFilePath, updated)
+}
+
+// UnsetFileValue removes a config value from the config file at the provided path,
+// this reports whether the config value was found in the config file.
+// Comments and the order of the remaining values are preserved in the same way
+// as SetFileValue.
+func UnsetFileValue(configFilePath string, configName string) (bool, error) {
+	data, err := readRawConfigFile(configFilePath)
+	if err != nil || len(data) == 0 {
+		return false, err
+	}
+
+	format, err := FileFormatFromPath(configFilePath)
+	if err != nil {
+		return false, err
+	}
+
+	segments := strings.Split(configName, ".")
+	var updated []byte
+	var removed bool
+	switch format {
+	case FileFormatYAML:
+		updated, removed, err = unsetYAMLValue(data, segments)
+	case FileFormatJSON:
+		updated, removed, err = unsetJSONValue(data, segments)
+	case FileFormatTOML:
+		updated, removed, err = unsetTOMLValue(data, segments)
+	}
+	if err != nil || !removed {
+		return false, err
+	}
+
+	return true, writeRawConfigFile(configFilePath, updated)
+}
+
+// ParseValue parses a config value provided in the same string form that is used
+// for environment variables as the registered type of the config value.
+// The config name can be prefixed with "profiles.<profile>." for values in a profile.
+// An error is returned for config values that are not registered.
+func (p *Provider) ParseValue(configName string, value string) (any, error) {
+	key, hasKey := p.keys[profileKeyName(configName)]
+	if !hasKey {
+		return nil, fmt.Errorf("unknown config value %q", configName)
+	}
+
+	if err := checkValue(key, value); err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", configName, err)
+	}
+
+	// Values with references are written as they are
+	// to be resolved when the config value is looked up.
+	if hasReferences(value) {
+		return value, nil
+	}
+
+	switch key.Type {
+	case ValueTypeBool:
+		return strconv.ParseBool(value)
+	case ValueTypeInt:
+		return strconv.ParseInt(value, 10, 64)
+	case ValueTypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case ValueTypeStringList:
+		return splitList(value), nil
+	case ValueTypeStringMap:
+		return splitMap(value), nil
+	}
+
+	return value, nil
+}
+
+// profileKeyName strips the "profiles.<profile>." prefix
+// from the name of a config value in a profile.
+func profileKeyName(configName string) string {
+	segments := strings.SplitN(configName, ".", 3)
+	if len(segments) == 3 && segments[0] == profilesSection && segments[2] != ProfileConfigName {
+		return segments[2]
+	}
+
+	return configName
+}
+
+// readRawConfigFile reads a config file to be edited, Windows line endings
+// are converted so config files can be edited line by line and are restored
+// when the config file is written.
+func readRawConfigFile(configFilePath string) ([]byte, error) {
+	data, err := os.ReadFile(configFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []byte{}, nil
+	}
+
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), err
+}
+
+func writeRawConfigFile(configFilePath string, data []byte) error {
+	existing, err := os.ReadFile(configFilePath)
+	if err == nil && bytes.Contains(existing, []byte("\r\n")) {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+
+	if dir := filepath.Dir(configFilePath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(configFilePath, data, 0644)
+}
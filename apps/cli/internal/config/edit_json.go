@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// jsonObject is a JSON object that preserves the order of its members
+// so config files can be updated without reordering existing values.
+type jsonObject struct {
+	members []*jsonMember
+}
+
+type jsonMember struct {
+	key   string
+	value any
+}
+
+func (o *jsonObject) index(key string) int {
+	for i, member := range o.members {
+		if member.key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("{")
+	for i, member := range o.members {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		key, err := json.Marshal(member.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+func setJSONValue(data []byte, segments []string, value any) ([]byte, error) {
+	root, err := parseJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// A value stored under the full config name takes precedence
+	// in the same way as when config values are looked up.
+	keySegments := segments
+	if root.index(strings.Join(segments, ".")) >= 0 && len(segments) > 1 {
+		keySegments = []string{strings.Join(segments, ".")}
+	}
+
+	section := root
+	for _, segment := range keySegments[:len(keySegments)-1] {
+		index := section.index(segment)
+		if index < 0 {
+			nested := &jsonObject{}
+			section.members = append(section.members, &jsonMember{key: segment, value: nested})
+			section = nested
+			continue
+		}
+
+		nested, isObject := section.members[index].value.(*jsonObject)
+		if !isObject {
+			return nil, fmt.Errorf("%q is not a section", segment)
+		}
+		section = nested
+	}
+
+	lastSegment := keySegments[len(keySegments)-1]
+	if index := section.index(lastSegment); index >= 0 {
+		section.members[index].value = value
+	} else {
+		section.members = append(section.members, &jsonMember{key: lastSegment, value: value})
+	}
+
+	return encodeJSONObject(root)
+}
+
+func unsetJSONValue(data []byte, segments []string) ([]byte, bool, error) {
+	root, err := parseJSONObject(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	removed := removeJSONValue(root, []string{strings.Join(segments, ".")})
+	if !removed && len(segments) > 1 {
+		removed = removeJSONValue(root, segments)
+	}
+
+	if !removed {
+		return nil, false, nil
+	}
+
+	encoded, err := encodeJSONObject(root)
+	return encoded, true, err
+}
+
+func removeJSONValue(section *jsonObject, segments []string) bool {
+	for _, segment := range segments[:len(segments)-1] {
+		index := section.index(segment)
+		if index < 0 {
+			return false
+		}
+
+		nested, isObject := section.members[index].value.(*jsonObject)
+		if !isObject {
+			return false
+		}
+		section = nested
+	}
+
+	index := section.index(segments[len(segments)-1])
+	if index < 0 {
+		return false
+	}
+
+	section.members = append(section.members[:index], section.members[index+1:]...)
+	return true
+}
+
+func parseJSONObject(data []byte) (*jsonObject, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &jsonObject{}, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	root, isObject := value.(*jsonObject)
+	if !isObject {
+		return nil, errors.New("the top level of a config file must be an object")
+	}
+
+	return root, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &jsonObject{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			object.members = append(object.members, &jsonMember{key: keyToken.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		items := []any{}
+		for decoder.More() {
+			item, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = decoder.Token()
+		return items, err
+	}
+
+	return token, nil
+}
+
+func encodeJSONObject(root *jsonObject) ([]byte, error) {
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
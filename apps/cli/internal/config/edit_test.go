@@ -0,0 +1,509 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetFileValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileName   string
+		initial    string
+		configName string
+		value      any
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "toml creates the config file",
+			fileName:   "celerity.config.toml",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want:       "connectProtocol = \"tcp\"\n",
+		},
+		{
+			name:     "toml replaces a value and keeps comments",
+			fileName: "celerity.config.toml",
+			initial: "# Connection settings\n" +
+				"connectProtocol = \"unix\" # local engine\n",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want: "# Connection settings\n" +
+				"connectProtocol = \"tcp\" # local engine\n",
+		},
+		{
+			name:     "toml adds a value to an existing table",
+			fileName: "celerity.config.toml",
+			initial: "[engine]\n" +
+				"endpoint = \"http://localhost:8325\"\n" +
+				"\n" +
+				"# Validate settings\n" +
+				"[validate]\n" +
+				"format = \"text\"\n",
+			configName: "engine.authMethod",
+			value:      "apiKey",
+			want: "[engine]\n" +
+				"endpoint = \"http://localhost:8325\"\n" +
+				"authMethod = \"apiKey\"\n" +
+				"\n" +
+				"# Validate settings\n" +
+				"[validate]\n" +
+				"format = \"text\"\n",
+		},
+		{
+			name:       "toml replaces a dotted key",
+			fileName:   "celerity.config.toml",
+			initial:    "engine.endpoint = \"http://localhost:8325\"\n",
+			configName: "engine.endpoint",
+			value:      "http://localhost:9000",
+			want:       "engine.endpoint = \"http://localhost:9000\"\n",
+		},
+		{
+			name:       "toml adds a dotted key next to existing dotted keys",
+			fileName:   "celerity.config.toml",
+			initial:    "engine.endpoint = \"http://localhost:8325\"\n",
+			configName: "engine.authMethod",
+			value:      "apiKey",
+			want: "engine.endpoint = \"http://localhost:8325\"\n" +
+				"engine.authMethod = \"apiKey\"\n",
+		},
+		{
+			name:       "toml replaces a value stored under a quoted full name",
+			fileName:   "celerity.config.toml",
+			initial:    "\"validate.format\" = \"text\"\n",
+			configName: "validate.format",
+			value:      "json",
+			want:       "\"validate.format\" = \"json\"\n",
+		},
+		{
+			name:     "toml replaces a multi-line string",
+			fileName: "celerity.config.toml",
+			initial: "description = \"\"\"\n" +
+				"first line\n" +
+				"second line\n" +
+				"\"\"\"\n" +
+				"connectProtocol = \"unix\"\n",
+			configName: "description",
+			value:      "single line",
+			want: "description = \"single line\"\n" +
+				"connectProtocol = \"unix\"\n",
+		},
+		{
+			name:     "toml replaces a multi-line array",
+			fileName: "celerity.config.toml",
+			initial: "[validate]\n" +
+				"blueprintFile = [\n" +
+				"  \"app.blueprint.yaml\", # main\n" +
+				"  \"jobs.blueprint.yaml\",\n" +
+				"]\n" +
+				"format = \"text\"\n",
+			configName: "validate.blueprintFile",
+			value:      []string{"other.blueprint.yaml"},
+			want: "[validate]\n" +
+				"blueprintFile = [\"other.blueprint.yaml\"]\n" +
+				"format = \"text\"\n",
+		},
+		{
+			name:     "toml ignores values in arrays of tables",
+			fileName: "celerity.config.toml",
+			initial: "[[plugins]]\n" +
+				"endpoint = \"http://localhost:8000\"\n" +
+				"\n" +
+				"[engine]\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+			configName: "engine.endpoint",
+			value:      "http://localhost:9000",
+			want: "[[plugins]]\n" +
+				"endpoint = \"http://localhost:8000\"\n" +
+				"\n" +
+				"[engine]\n" +
+				"endpoint = \"http://localhost:9000\"\n",
+		},
+		{
+			name:       "toml fails for values in an array of tables",
+			fileName:   "celerity.config.toml",
+			initial:    "[[plugins]]\nendpoint = \"http://localhost:8000\"\n",
+			configName: "plugins.endpoint",
+			value:      "http://localhost:9000",
+			wantErr:    "\"plugins\" is defined as an array of tables",
+		},
+		{
+			name:       "toml fails for values in an inline table",
+			fileName:   "celerity.config.toml",
+			initial:    "engine = { endpoint = \"http://localhost:8325\" }\n",
+			configName: "engine.endpoint",
+			value:      "http://localhost:9000",
+			wantErr:    "edit the config file manually",
+		},
+		{
+			name:       "toml creates a missing table",
+			fileName:   "celerity.config.toml",
+			initial:    "connectProtocol = \"tcp\"\n\n",
+			configName: "engine.auth.apiKey",
+			value:      "test-key",
+			want: "connectProtocol = \"tcp\"\n" +
+				"\n" +
+				"[engine.auth]\n" +
+				"apiKey = \"test-key\"\n",
+		},
+		{
+			name:     "toml creates a missing table for a profile",
+			fileName: "celerity.config.toml",
+			initial: "[profiles.dev]\n" +
+				"connectProtocol = \"tcp\"\n",
+			configName: "profiles.dev.engine.endpoint",
+			value:      "http://localhost:9000",
+			want: "[profiles.dev]\n" +
+				"connectProtocol = \"tcp\"\n" +
+				"\n" +
+				"[profiles.dev.engine]\n" +
+				"endpoint = \"http://localhost:9000\"\n",
+		},
+		{
+			name:     "toml adds a top level value before the first table",
+			fileName: "celerity.config.toml",
+			initial: "[engine]\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want: "connectProtocol = \"tcp\"\n" +
+				"\n" +
+				"[engine]\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+		},
+		{
+			name:       "toml writes typed values",
+			fileName:   "celerity.config.toml",
+			initial:    "[validate]\n",
+			configName: "validate.parallelism",
+			value:      int64(8),
+			want:       "[validate]\nparallelism = 8\n",
+		},
+		{
+			name:     "toml keeps windows line endings",
+			fileName: "celerity.config.toml",
+			initial: "# Connection settings\r\n" +
+				"[engine]\r\n" +
+				"endpoint = \"http://localhost:8325\"\r\n",
+			configName: "engine.authMethod",
+			value:      "apiKey",
+			want: "# Connection settings\r\n" +
+				"[engine]\r\n" +
+				"endpoint = \"http://localhost:8325\"\r\n" +
+				"authMethod = \"apiKey\"\r\n",
+		},
+		{
+			name:     "yaml replaces a value and keeps comments",
+			fileName: "celerity.config.yaml",
+			initial: "# Connection settings\n" +
+				"connectProtocol: unix # local engine\n" +
+				"engine:\n" +
+				"  endpoint: http://localhost:8325\n",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want: "# Connection settings\n" +
+				"connectProtocol: tcp # local engine\n" +
+				"engine:\n" +
+				"  endpoint: http://localhost:8325\n",
+		},
+		{
+			name:     "yaml creates missing mappings",
+			fileName: "celerity.config.yaml",
+			initial: "engine:\n" +
+				"  endpoint: http://localhost:8325\n",
+			configName: "engine.auth.apiKey",
+			value:      "test-key",
+			want: "engine:\n" +
+				"  endpoint: http://localhost:8325\n" +
+				"  auth:\n" +
+				"    apiKey: test-key\n",
+		},
+		{
+			name:     "yaml replaces a list",
+			fileName: "celerity.config.yaml",
+			initial: "validate:\n" +
+				"  blueprintFile:\n" +
+				"    - app.blueprint.yaml\n" +
+				"    - jobs.blueprint.yaml\n",
+			configName: "validate.blueprintFile",
+			value:      []string{"other.blueprint.yaml"},
+			want: "validate:\n" +
+				"  blueprintFile:\n" +
+				"    - other.blueprint.yaml\n",
+		},
+		{
+			name:       "yaml fails when a parent is not a mapping",
+			fileName:   "celerity.config.yaml",
+			initial:    "engine: http://localhost:8325\n",
+			configName: "engine.endpoint",
+			value:      "http://localhost:9000",
+			wantErr:    "failed to set \"engine.endpoint\"",
+		},
+		{
+			name:     "yaml keeps windows line endings",
+			fileName: "celerity.config.yaml",
+			initial: "engine:\r\n" +
+				"  endpoint: http://localhost:8325\r\n",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want: "engine:\r\n" +
+				"  endpoint: http://localhost:8325\r\n" +
+				"connectProtocol: tcp\r\n",
+		},
+		{
+			name:     "json keeps the order of existing values",
+			fileName: "celerity.config.json",
+			initial: "{\n" +
+				"  \"engine\": {\"endpoint\": \"http://localhost:8325\"},\n" +
+				"  \"connectProtocol\": \"unix\"\n" +
+				"}\n",
+			configName: "connectProtocol",
+			value:      "tcp",
+			want: "{\n" +
+				"  \"engine\": {\n" +
+				"    \"endpoint\": \"http://localhost:8325\"\n" +
+				"  },\n" +
+				"  \"connectProtocol\": \"tcp\"\n" +
+				"}\n",
+		},
+		{
+			name:       "json creates missing objects",
+			fileName:   "celerity.config.json",
+			initial:    "{\"connectProtocol\": \"tcp\"}\n",
+			configName: "engine.auth.apiKey",
+			value:      "test-key",
+			want: "{\n" +
+				"  \"connectProtocol\": \"tcp\",\n" +
+				"  \"engine\": {\n" +
+				"    \"auth\": {\n" +
+				"      \"apiKey\": \"test-key\"\n" +
+				"    }\n" +
+				"  }\n" +
+				"}\n",
+		},
+		{
+			name:       "json fails when a parent is not an object",
+			fileName:   "celerity.config.json",
+			initial:    "{\"engine\": \"http://localhost:8325\"}\n",
+			configName: "engine.endpoint",
+			value:      "http://localhost:9000",
+			wantErr:    "failed to set \"engine.endpoint\"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeTestConfigFile(t, test.fileName, test.initial)
+
+			err := SetFileValue(path, test.configName, test.value)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertConfigFileContent(t, path, test.want)
+		})
+	}
+}
+
+func TestUnsetFileValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		initial     string
+		configName  string
+		want        string
+		wantRemoved bool
+		wantErr     string
+	}{
+		{
+			name:     "toml removes a value and keeps comments",
+			fileName: "celerity.config.toml",
+			initial: "# Connection settings\n" +
+				"connectProtocol = \"unix\" # local engine\n" +
+				"\n" +
+				"[engine]\n" +
+				"# The endpoint of the engine\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+			configName: "connectProtocol",
+			want: "# Connection settings\n" +
+				"\n" +
+				"[engine]\n" +
+				"# The endpoint of the engine\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+			wantRemoved: true,
+		},
+		{
+			name:     "toml removes a dotted key",
+			fileName: "celerity.config.toml",
+			initial: "engine.endpoint = \"http://localhost:8325\"\n" +
+				"engine.authMethod = \"apiKey\"\n",
+			configName:  "engine.authMethod",
+			want:        "engine.endpoint = \"http://localhost:8325\"\n",
+			wantRemoved: true,
+		},
+		{
+			name:     "toml removes a multi-line string",
+			fileName: "celerity.config.toml",
+			initial: "description = '''\n" +
+				"connectProtocol = \"unix\"\n" +
+				"'''\n" +
+				"connectProtocol = \"tcp\"\n",
+			configName:  "description",
+			want:        "connectProtocol = \"tcp\"\n",
+			wantRemoved: true,
+		},
+		{
+			name:     "toml removes a multi-line array",
+			fileName: "celerity.config.toml",
+			initial: "[validate]\n" +
+				"blueprintFile = [\n" +
+				"  \"app.blueprint.yaml\",\n" +
+				"  \"jobs.blueprint.yaml\",\n" +
+				"]\n" +
+				"format = \"text\"\n",
+			configName: "validate.blueprintFile",
+			want: "[validate]\n" +
+				"format = \"text\"\n",
+			wantRemoved: true,
+		},
+		{
+			name:     "toml removes a value in a profile",
+			fileName: "celerity.config.toml",
+			initial: "connectProtocol = \"unix\"\n" +
+				"\n" +
+				"[profiles.dev]\n" +
+				"connectProtocol = \"tcp\"\n",
+			configName: "profiles.dev.connectProtocol",
+			want: "connectProtocol = \"unix\"\n" +
+				"\n" +
+				"[profiles.dev]\n",
+			wantRemoved: true,
+		},
+		{
+			name:     "toml does not remove values in arrays of tables",
+			fileName: "celerity.config.toml",
+			initial: "[[engine]]\n" +
+				"endpoint = \"http://localhost:8325\"\n",
+			configName: "engine.endpoint",
+			wantErr:    "\"engine\" is defined as an array of tables",
+		},
+		{
+			name:     "toml keeps windows line endings",
+			fileName: "celerity.config.toml",
+			initial: "connectProtocol = \"unix\"\r\n" +
+				"[engine]\r\n" +
+				"endpoint = \"http://localhost:8325\"\r\n",
+			configName:  "connectProtocol",
+			want:        "[engine]\r\nendpoint = \"http://localhost:8325\"\r\n",
+			wantRemoved: true,
+		},
+		{
+			name:       "toml reports values that are not set",
+			fileName:   "celerity.config.toml",
+			initial:    "connectProtocol = \"unix\"\n",
+			configName: "engine.endpoint",
+			want:       "connectProtocol = \"unix\"\n",
+		},
+		{
+			name:     "yaml removes a value and keeps comments",
+			fileName: "celerity.config.yaml",
+			initial: "# Connection settings\n" +
+				"connectProtocol: unix\n" +
+				"engine:\n" +
+				"  endpoint: http://localhost:8325 # local engine\n" +
+				"  authMethod: apiKey\n",
+			configName: "engine.authMethod",
+			want: "# Connection settings\n" +
+				"connectProtocol: unix\n" +
+				"engine:\n" +
+				"  endpoint: http://localhost:8325 # local engine\n",
+			wantRemoved: true,
+		},
+		{
+			name:       "yaml reports values that are not set",
+			fileName:   "celerity.config.yaml",
+			initial:    "connectProtocol: unix\n",
+			configName: "engine.endpoint",
+			want:       "connectProtocol: unix\n",
+		},
+		{
+			name:     "json removes a value",
+			fileName: "celerity.config.json",
+			initial: "{\n" +
+				"  \"connectProtocol\": \"unix\",\n" +
+				"  \"engine\": {\"endpoint\": \"http://localhost:8325\"}\n" +
+				"}\n",
+			configName: "connectProtocol",
+			want: "{\n" +
+				"  \"engine\": {\n" +
+				"    \"endpoint\": \"http://localhost:8325\"\n" +
+				"  }\n" +
+				"}\n",
+			wantRemoved: true,
+		},
+		{
+			name:       "json reports values that are not set",
+			fileName:   "celerity.config.json",
+			initial:    "{\"connectProtocol\": \"unix\"}\n",
+			configName: "engine.endpoint",
+			want:       "{\"connectProtocol\": \"unix\"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeTestConfigFile(t, test.fileName, test.initial)
+
+			removed, err := UnsetFileValue(path, test.configName)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if removed != test.wantRemoved {
+				t.Errorf("expected removed to be %t, got %t", test.wantRemoved, removed)
+			}
+
+			assertConfigFileContent(t, path, test.want)
+		})
+	}
+}
+
+func writeTestConfigFile(t *testing.T, fileName string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), fileName)
+	if content == "" {
+		return path
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func assertConfigFileContent(t *testing.T, path string, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("unexpected config file content\nwant:\n%s\ngot:\n%s", want, data)
+	}
+}
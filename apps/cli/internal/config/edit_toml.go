@@ -0,0 +1,511 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// The TOML library used by the CLI does not provide a way to edit documents
+// without losing comments, so TOML config files are edited line by line.
+// Tables, dotted keys and values that span multiple lines are supported,
+// values inside inline tables and arrays of tables can not be edited.
+
+type tomlLine struct {
+	// table is the table that the line belongs to.
+	table []string
+	// key is the full key of a key/value line,
+	// relative to the table that the line belongs to.
+	key []string
+	// keyText is the key as written in the file.
+	keyText       string
+	isHeader      bool
+	isArrayHeader bool
+	// end is the index of the last line of a value
+	// that spans multiple lines.
+	end int
+}
+
+func (l *tomlLine) fullKey() []string {
+	return append(slices.Clone(l.table), l.key...)
+}
+
+func setTOMLValue(data []byte, segments []string, value any) ([]byte, error) {
+	encoded, err := encodeTOMLValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(data)
+	parsed, err := parseTOMLLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotInlineTable(parsed, segments); err != nil {
+		return nil, err
+	}
+
+	if index := findTOMLKey(lines, parsed, segments); index >= 0 {
+		line := parsed[index]
+		indent := leadingWhitespace(lines[index])
+		comment := ""
+		if line.end == index {
+			comment = trailingComment(lines[index])
+		}
+		replacement := fmt.Sprintf("%s%s = %s%s", indent, line.keyText, encoded, comment)
+		lines = slices.Replace(lines, index, line.end+1, replacement)
+		return joinLines(lines), nil
+	}
+
+	parent := segments[:len(segments)-1]
+	if index := lastTOMLLineInSection(parsed, parent); index >= 0 {
+		line := parsed[index]
+		relativeKey := formatTOMLKey(segments[len(line.table):])
+		indent := ""
+		if !line.isHeader {
+			indent = leadingWhitespace(lines[index])
+		}
+		lines = slices.Insert(lines, line.end+1, fmt.Sprintf("%s%s = %s", indent, relativeKey, encoded))
+		return joinLines(lines), nil
+	}
+
+	// Tables that have been defined with dotted keys can not be
+	// defined again with a table header.
+	if index := lastTOMLDottedKeyInSection(parsed, parent); index >= 0 {
+		line := parsed[index]
+		relativeKey := formatTOMLKey(segments[len(line.table):])
+		indent := leadingWhitespace(lines[index])
+		lines = slices.Insert(lines, line.end+1, fmt.Sprintf("%s%s = %s", indent, relativeKey, encoded))
+		return joinLines(lines), nil
+	}
+
+	if len(parent) == 0 {
+		insertAt := firstTOMLHeader(parsed)
+		entry := []string{fmt.Sprintf("%s = %s", formatTOMLKey(segments), encoded)}
+		if insertAt < len(lines) {
+			entry = append(entry, "")
+		}
+		lines = slices.Insert(lines, insertAt, entry...)
+		return joinLines(lines), nil
+	}
+
+	lines = trimTrailingBlankLines(lines)
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(
+		lines,
+		fmt.Sprintf("[%s]", formatTOMLKey(parent)),
+		fmt.Sprintf("%s = %s", formatTOMLKey(segments[len(segments)-1:]), encoded),
+	)
+	return joinLines(lines), nil
+}
+
+func unsetTOMLValue(data []byte, segments []string) ([]byte, bool, error) {
+	lines := splitLines(data)
+	parsed, err := parseTOMLLines(lines)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := checkNotInlineTable(parsed, segments); err != nil {
+		return nil, false, err
+	}
+
+	index := findTOMLKey(lines, parsed, segments)
+	if index < 0 {
+		return nil, false, nil
+	}
+
+	lines = slices.Delete(lines, index, parsed[index].end+1)
+	return joinLines(lines), true, nil
+}
+
+func parseTOMLLines(lines []string) ([]*tomlLine, error) {
+	parsed := make([]*tomlLine, len(lines))
+	table := []string{}
+	inArrayTable := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			isArrayHeader := strings.HasPrefix(trimmed, "[[")
+			headerText := strings.Trim(stripTOMLComment(trimmed), "[] \t")
+			header, err := parseTOMLKey(headerText)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			table = header
+			inArrayTable = isArrayHeader
+			parsed[i] = &tomlLine{
+				table:         header,
+				isHeader:      true,
+				isArrayHeader: isArrayHeader,
+				end:           i,
+			}
+			continue
+		}
+
+		keyText, valueText, hasValue := cutTOMLKeyValue(lines[i])
+		if !hasValue {
+			return nil, fmt.Errorf("line %d: expected a key/value pair", i+1)
+		}
+
+		key, err := parseTOMLKey(keyText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		end, err := tomlValueEnd(lines, i, valueText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		// Values in arrays of tables are not addressable with config names.
+		if !inArrayTable {
+			parsed[i] = &tomlLine{
+				table:   table,
+				key:     key,
+				keyText: strings.TrimSpace(keyText),
+				end:     end,
+			}
+		}
+		i = end
+	}
+
+	return parsed, nil
+}
+
+func findTOMLKey(lines []string, parsed []*tomlLine, segments []string) int {
+	fullName := strings.Join(segments, ".")
+	for i, line := range parsed {
+		if line == nil || line.isHeader {
+			continue
+		}
+
+		fullKey := line.fullKey()
+		// A value stored under the full config name as a quoted key
+		// takes precedence in the same way as when config values are looked up.
+		if slices.Equal(fullKey, segments) ||
+			(len(line.table) == 0 && len(line.key) == 1 && line.key[0] == fullName) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// lastTOMLLineInSection finds the line after which a new value can be added
+// to the section with the provided name, this is the last value in the section
+// or the table header if the section is an empty table.
+func lastTOMLLineInSection(parsed []*tomlLine, section []string) int {
+	last := -1
+	for i, line := range parsed {
+		if line == nil || line.isArrayHeader {
+			continue
+		}
+
+		if line.isHeader {
+			if slices.Equal(line.table, section) && last < 0 {
+				last = i
+			}
+			continue
+		}
+
+		fullKey := line.fullKey()
+		if slices.Equal(fullKey[:len(fullKey)-1], section) {
+			last = i
+		}
+	}
+
+	return last
+}
+
+// lastTOMLDottedKeyInSection finds the last value defined with a dotted key
+// in the closest ancestor of the section with the provided name
+// where the ancestor was implicitly defined by the dotted key.
+func lastTOMLDottedKeyInSection(parsed []*tomlLine, section []string) int {
+	for depth := len(section); depth > 0; depth -= 1 {
+		last := -1
+		for i, line := range parsed {
+			if line == nil || line.isHeader || len(line.table) >= depth {
+				continue
+			}
+
+			fullKey := line.fullKey()
+			if len(fullKey) > depth && slices.Equal(fullKey[:depth], section[:depth]) {
+				last = i
+			}
+		}
+
+		if last >= 0 {
+			return last
+		}
+	}
+
+	return -1
+}
+
+func firstTOMLHeader(parsed []*tomlLine) int {
+	for i, line := range parsed {
+		if line != nil && line.isHeader {
+			return i
+		}
+	}
+
+	return len(parsed)
+}
+
+func checkNotInlineTable(parsed []*tomlLine, segments []string) error {
+	for _, line := range parsed {
+		if line != nil && line.isArrayHeader && len(line.table) < len(segments) &&
+			slices.Equal(line.table, segments[:len(line.table)]) {
+			return fmt.Errorf(
+				"%q is defined as an array of tables, edit the config file manually to change it",
+				strings.Join(line.table, "."),
+			)
+		}
+
+		if line == nil || line.isHeader {
+			continue
+		}
+
+		fullKey := line.fullKey()
+		if len(fullKey) < len(segments) && slices.Equal(fullKey, segments[:len(fullKey)]) {
+			return fmt.Errorf(
+				"%q is defined as an inline table or a value, edit the config file manually to change it",
+				strings.Join(fullKey, "."),
+			)
+		}
+	}
+
+	return nil
+}
+
+// tomlValueEnd finds the last line of a value that starts on the provided line,
+// multi-line strings and arrays can span multiple lines.
+func tomlValueEnd(lines []string, start int, valueText string) (int, error) {
+	value := strings.TrimSpace(valueText)
+	for _, delimiter := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, delimiter) {
+			if strings.Count(value, delimiter) >= 2 {
+				return start, nil
+			}
+			for i := start + 1; i < len(lines); i++ {
+				if strings.Contains(lines[i], delimiter) {
+					return i, nil
+				}
+			}
+			return 0, errors.New("unterminated multi-line string")
+		}
+	}
+
+	depth := bracketDepth(value)
+	i := start
+	for depth > 0 {
+		i += 1
+		if i >= len(lines) {
+			return 0, errors.New("unterminated array")
+		}
+		depth += bracketDepth(lines[i])
+	}
+
+	return i, nil
+}
+
+// bracketDepth counts the change in the nesting of arrays and inline tables
+// on a line, ignoring brackets in strings and comments.
+func bracketDepth(line string) int {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, char := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if char == '\\' && quote == '"' {
+				escaped = true
+			} else if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '#':
+			return depth
+		case char == '[' || char == '{':
+			depth += 1
+		case char == ']' || char == '}':
+			depth -= 1
+		}
+	}
+
+	return depth
+}
+
+func cutTOMLKeyValue(line string) (string, string, bool) {
+	var quote rune
+	for i, char := range line {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '=':
+			return line[:i], line[i+1:], true
+		}
+	}
+
+	return "", "", false
+}
+
+// parseTOMLKey parses a bare, quoted or dotted key.
+func parseTOMLKey(keyText string) ([]string, error) {
+	key := []string{}
+	current := strings.Builder{}
+	var quote rune
+	for _, char := range strings.TrimSpace(keyText) {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '.':
+			key = append(key, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(char)
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quoted key %q", keyText)
+	}
+
+	return append(key, strings.TrimSpace(current.String())), nil
+}
+
+func formatTOMLKey(segments []string) string {
+	formatted := make([]string, len(segments))
+	for i, segment := range segments {
+		if isBareTOMLKey(segment) {
+			formatted[i] = segment
+		} else {
+			formatted[i] = fmt.Sprintf("%q", segment)
+		}
+	}
+	return strings.Join(formatted, ".")
+}
+
+func isBareTOMLKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, char := range key {
+		isBare := (char >= 'a' && char <= 'z') ||
+			(char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') ||
+			char == '_' || char == '-'
+		if !isBare {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeTOMLValue(value any) (string, error) {
+	if stringMap, isMap := value.(map[string]string); isMap {
+		entries := []string{}
+		for _, key := range slices.Sorted(maps.Keys(stringMap)) {
+			encoded, err := encodeTOMLValue(stringMap[key])
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, fmt.Sprintf("%s = %s", formatTOMLKey([]string{key}), encoded))
+		}
+		return fmt.Sprintf("{ %s }", strings.Join(entries, ", ")), nil
+	}
+
+	buf := &bytes.Buffer{}
+	err := toml.NewEncoder(buf).Encode(map[string]any{"value": value})
+	if err != nil {
+		return "", err
+	}
+
+	_, encoded, _ := strings.Cut(strings.TrimSpace(buf.String()), "=")
+	return strings.TrimSpace(encoded), nil
+}
+
+func trailingComment(line string) string {
+	_, valueText, _ := cutTOMLKeyValue(line)
+	stripped := stripTOMLComment(valueText)
+	if len(stripped) == len(valueText) {
+		return ""
+	}
+
+	return " " + strings.TrimSpace(valueText[len(stripped):])
+}
+
+// stripTOMLComment removes a trailing comment from a line,
+// ignoring "#" characters in strings.
+func stripTOMLComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, char := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if char == '\\' && quote == '"' {
+				escaped = true
+			} else if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func splitLines(data []byte) []string {
+	content := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+func joinLines(lines []string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func trimTrailingBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
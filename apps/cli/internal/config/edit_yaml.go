@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func setYAMLValue(data []byte, segments []string, value any) ([]byte, error) {
+	doc, root, err := parseYAMLDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return nil, err
+	}
+
+	// A value stored under the full config name takes precedence
+	// in the same way as when config values are looked up.
+	section := root
+	keySegments := segments
+	if index := yamlMappingIndex(root, strings.Join(segments, ".")); index >= 0 && len(segments) > 1 {
+		keySegments = []string{strings.Join(segments, ".")}
+	}
+
+	for _, segment := range keySegments[:len(keySegments)-1] {
+		index := yamlMappingIndex(section, segment)
+		if index < 0 {
+			nested := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			section.Content = append(section.Content, yamlKeyNode(segment), nested)
+			section = nested
+			continue
+		}
+
+		nested := section.Content[index+1]
+		if nested.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%q is not a section", segment)
+		}
+		section = nested
+	}
+
+	lastSegment := keySegments[len(keySegments)-1]
+	index := yamlMappingIndex(section, lastSegment)
+	if index < 0 {
+		section.Content = append(section.Content, yamlKeyNode(lastSegment), valueNode)
+	} else {
+		existing := section.Content[index+1]
+		valueNode.HeadComment = existing.HeadComment
+		valueNode.LineComment = existing.LineComment
+		valueNode.FootComment = existing.FootComment
+		section.Content[index+1] = valueNode
+	}
+
+	return encodeYAMLDocument(doc)
+}
+
+func unsetYAMLValue(data []byte, segments []string) ([]byte, bool, error) {
+	doc, root, err := parseYAMLDocument(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	removed := removeYAMLValue(root, []string{strings.Join(segments, ".")})
+	if !removed && len(segments) > 1 {
+		removed = removeYAMLValue(root, segments)
+	}
+
+	if !removed {
+		return nil, false, nil
+	}
+
+	encoded, err := encodeYAMLDocument(doc)
+	return encoded, true, err
+}
+
+func removeYAMLValue(section *yaml.Node, segments []string) bool {
+	for _, segment := range segments[:len(segments)-1] {
+		index := yamlMappingIndex(section, segment)
+		if index < 0 || section.Content[index+1].Kind != yaml.MappingNode {
+			return false
+		}
+		section = section.Content[index+1]
+	}
+
+	index := yamlMappingIndex(section, segments[len(segments)-1])
+	if index < 0 {
+		return false
+	}
+
+	section.Content = append(section.Content[:index], section.Content[index+2:]...)
+	return true
+}
+
+func parseYAMLDocument(data []byte) (*yaml.Node, *yaml.Node, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, nil, err
+	}
+
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+
+	if len(doc.Content) == 0 {
+		// Comments are dropped when parsing a document without any values
+		// so they are carried over to the new top level mapping.
+		doc.Content = []*yaml.Node{{
+			Kind:        yaml.MappingNode,
+			Tag:         "!!map",
+			HeadComment: strings.TrimSpace(string(data)),
+		}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("the top level of a config file must be a mapping")
+	}
+
+	return doc, root, nil
+}
+
+func encodeYAMLDocument(doc *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func yamlMappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func yamlKeyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
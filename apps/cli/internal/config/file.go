@@ -0,0 +1,159 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func readConfigFile(configFilePath string) (map[string]any, error) {
+	configFile, err := os.Open(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer configFile.Close()
+
+	format, err := FileFormatFromPath(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]any{}
+	switch format {
+	case FileFormatYAML:
+		err = yaml.NewDecoder(configFile).Decode(&config)
+		// An empty YAML document is a valid config file.
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case FileFormatJSON:
+		err = json.NewDecoder(configFile).Decode(&config)
+	case FileFormatTOML:
+		_, err = toml.NewDecoder(configFile).Decode(&config)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// FileFormat is the format of a config file.
+type FileFormat string
+
+const (
+	// FileFormatYAML is used for files with a ".yaml" or ".yml" extension.
+	FileFormatYAML FileFormat = "yaml"
+	// FileFormatJSON is used for files with a ".json" extension.
+	FileFormatJSON FileFormat = "json"
+	// FileFormatTOML is used for files with a ".toml" extension.
+	FileFormatTOML FileFormat = "toml"
+)
+
+var (
+	// FileExtensions is a list of the file extensions supported
+	// for config files in order of preference.
+	FileExtensions = []string{".toml", ".yaml", ".yml", ".json"}
+)
+
+// FileFormatFromPath determines the format of a config file from its extension,
+// ErrUnsupportedConfigFileFormat is returned for unsupported file extensions.
+func FileFormatFromPath(path string) (FileFormat, error) {
+	switch {
+	case strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml"):
+		return FileFormatYAML, nil
+	case strings.HasSuffix(path, ".json"):
+		return FileFormatJSON, nil
+	case strings.HasSuffix(path, ".toml"):
+		return FileFormatTOML, nil
+	}
+
+	return "", ErrUnsupportedConfigFileFormat
+}
+
+type configLayer struct {
+	path   string
+	config map[string]any
+}
+
+// loadConfigLayer reads a config file as a layer to be merged with
+// other config files, nil is returned when the config file does not exist.
+func loadConfigLayer(configFilePath string) (*configLayer, error) {
+	rawConfig, err := readConfigFile(configFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file %q: %w", configFilePath, err)
+	}
+
+	config := map[string]any{}
+	mergeConfig(config, rawConfig)
+	return &configLayer{
+		path:   configFilePath,
+		config: config,
+	}, nil
+}
+
+// mergeConfig merges the source config into the destination config.
+// Keys that are full config names (e.g. "validate.blueprintFile") are expanded
+// into nested sections so values in different config files can be merged
+// regardless of how they are written.
+func mergeConfig(dest map[string]any, source map[string]any) {
+	for key, value := range source {
+		segments := strings.Split(key, ".")
+		section := dest
+		for _, segment := range segments[:len(segments)-1] {
+			nested, isSection := toMap(section[segment])
+			if !isSection {
+				nested = map[string]any{}
+			}
+			section[segment] = nested
+			section = nested
+		}
+
+		lastSegment := segments[len(segments)-1]
+		sourceSection, isSourceSection := toMap(value)
+		if !isSourceSection {
+			section[lastSegment] = value
+			continue
+		}
+
+		destSection, isDestSection := toMap(section[lastSegment])
+		if !isDestSection {
+			destSection = map[string]any{}
+		}
+		mergeConfig(destSection, sourceSection)
+		section[lastSegment] = destSection
+	}
+}
+
+func writableConfigFilePath(
+	configFilePath string,
+	userConfigFilePath string,
+	configFilePaths []string,
+) string {
+	if configFilePath != "" {
+		return configFilePath
+	}
+
+	for _, path := range slices.Backward(configFilePaths) {
+		if path == userConfigFilePath {
+			continue
+		}
+		if exists, _ := pathExists(path); exists {
+			return path
+		}
+	}
+
+	return ConfigFileNames[0]
+}
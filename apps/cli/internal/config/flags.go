@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mapFlagValue is a flag value for maps of strings that is provided as
+// repeated "key=value" pairs.
+// Unlike pflag's map flags, pairs are not split on commas
+// so values can contain commas and quotes.
+type mapFlagValue struct {
+	pairs   []string
+	changed bool
+}
+
+func newMapFlagValue(defaultPairs []string) *mapFlagValue {
+	return &mapFlagValue{
+		pairs: defaultPairs,
+	}
+}
+
+func (v *mapFlagValue) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected a value in the form \"key=value\", received %q", value)
+	}
+
+	// The first value provided by the user replaces the default pairs.
+	if !v.changed {
+		v.pairs = []string{}
+		v.changed = true
+	}
+	v.pairs = append(v.pairs, value)
+	return nil
+}
+
+func (v *mapFlagValue) Type() string {
+	return "key=value"
+}
+
+func (v *mapFlagValue) String() string {
+	return "[" + strings.Join(v.pairs, ",") + "]"
+}
+
+func (v *mapFlagValue) Append(value string) error {
+	return v.Set(value)
+}
+
+func (v *mapFlagValue) Replace(values []string) error {
+	v.pairs = []string{}
+	for _, value := range values {
+		if err := v.Set(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *mapFlagValue) GetSlice() []string {
+	return v.pairs
+}
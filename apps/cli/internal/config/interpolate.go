@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// ReferenceEnv is the kind of reference that is resolved
+	// from an environment variable, e.g. "${env:CELERITY_API_KEY}".
+	ReferenceEnv = "env"
+	// ReferenceFile is the kind of reference that is resolved
+	// from the contents of a file, e.g. "${file:~/.secrets/celerity-api-key}".
+	// Relative paths are resolved from the directory of the config file
+	// that contains the reference.
+	ReferenceFile = "file"
+	// ReferenceCmd is the kind of reference that is resolved
+	// from the output of a shell command, e.g. "${cmd:op read op://dev/celerity/api-key}".
+	ReferenceCmd = "cmd"
+
+	referenceCmdTimeout = 30 * time.Second
+)
+
+var (
+	// A reference can be escaped with an extra "$" to be used as a literal,
+	// e.g. "$${env:HOME}" resolves to "${env:HOME}".
+	referencePattern = regexp.MustCompile(`\$?\$\{(env|file|cmd):([^}]+)\}`)
+)
+
+// Interpolator resolves "${env:NAME}", "${file:path}" and "${cmd:command}"
+// references in config values.
+// Resolved values are cached so each reference is only resolved once,
+// values resolved from files and commands are treated as secrets
+// and are added to the provided redactor.
+//
+// Files and commands can be used to read or run anything on the user's machine,
+// so file and command references are only resolved from files that have been
+// trusted with TrustFile unless they have been allowed for all files with
+// AllowAllReferences, this prevents config files in a cloned project
+// from running commands when a config value is looked up.
+type Interpolator struct {
+	redactor           *Redactor
+	resolved           map[string]string
+	trustedFiles       map[string]bool
+	allowAllReferences bool
+}
+
+// NewInterpolator creates a new interpolator for config values that
+// adds resolved secrets to the provided redactor.
+func NewInterpolator(redactor *Redactor) *Interpolator {
+	return &Interpolator{
+		redactor:     redactor,
+		resolved:     map[string]string{},
+		trustedFiles: map[string]bool{},
+	}
+}
+
+// TrustFile allows file and command references to be resolved
+// from the file at the provided path.
+func (i *Interpolator) TrustFile(path string) {
+	i.trustedFiles[absPath(path)] = true
+}
+
+// AllowAllReferences allows file and command references
+// to be resolved from any file.
+func (i *Interpolator) AllowAllReferences() {
+	i.allowAllReferences = true
+}
+
+// Interpolate resolves references in a config value, strings are
+// interpolated and lists and maps are walked to interpolate the strings they contain.
+// The source path is the path of the file that contains the value,
+// relative paths in file references are resolved from the directory of the file.
+func (i *Interpolator) Interpolate(value any, sourcePath string) (any, error) {
+	switch typedValue := value.(type) {
+	case string:
+		return i.InterpolateString(typedValue, sourcePath)
+	case []any:
+		interpolated := make([]any, len(typedValue))
+		for index, item := range typedValue {
+			interpolatedItem, err := i.Interpolate(item, sourcePath)
+			if err != nil {
+				return nil, err
+			}
+			interpolated[index] = interpolatedItem
+		}
+		return interpolated, nil
+	}
+
+	if section, isSection := toMap(value); isSection {
+		interpolated := make(map[string]any, len(section))
+		for key, item := range section {
+			interpolatedItem, err := i.Interpolate(item, sourcePath)
+			if err != nil {
+				return nil, err
+			}
+			interpolated[key] = interpolatedItem
+		}
+		return interpolated, nil
+	}
+
+	return value, nil
+}
+
+// InterpolateString resolves references in a string value
+// from the file at the provided source path.
+func (i *Interpolator) InterpolateString(value string, sourcePath string) (string, error) {
+	var errs []error
+	interpolated := referencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		groups := referencePattern.FindStringSubmatch(match)
+		resolved, err := i.resolve(groups[1], strings.TrimSpace(groups[2]), sourcePath)
+		if err != nil {
+			errs = append(errs, err)
+			return ""
+		}
+		return resolved
+	})
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	return interpolated, nil
+}
+
+func (i *Interpolator) resolve(kind string, arg string, sourcePath string) (string, error) {
+	if kind != ReferenceEnv && !i.allowAllReferences && !i.trustedFiles[absPath(sourcePath)] {
+		return "", fmt.Errorf(
+			"%q reference in %q was refused, file and command references are only resolved "+
+				"from the user-level config file or the config file provided with --config, "+
+				"use --allow-cmd-references to resolve them from other files",
+			kind,
+			sourcePath,
+		)
+	}
+
+	baseDir := filepath.Dir(sourcePath)
+	cacheKey := fmt.Sprintf("%s:%s:%s", kind, baseDir, arg)
+	if resolved, isResolved := i.resolved[cacheKey]; isResolved {
+		return resolved, nil
+	}
+
+	var resolved string
+	var err error
+	switch kind {
+	case ReferenceEnv:
+		resolved, err = resolveEnvReference(arg)
+	case ReferenceFile:
+		resolved, err = resolveFileReference(arg, baseDir)
+	case ReferenceCmd:
+		resolved, err = resolveCmdReference(arg, baseDir)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if kind != ReferenceEnv {
+		i.redactor.Add(resolved)
+	}
+	i.resolved[cacheKey] = resolved
+	return resolved, nil
+}
+
+func resolveEnvReference(name string) (string, error) {
+	value, isSet := os.LookupEnv(name)
+	if !isSet {
+		return "", fmt.Errorf("environment variable %q referenced in config is not set", name)
+	}
+
+	return value, nil
+}
+
+func resolveFileReference(path string, baseDir string) (string, error) {
+	resolvedPath, err := expandPath(path, baseDir)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %q referenced in config: %w", path, err)
+	}
+
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+func resolveCmdReference(command string, baseDir string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), referenceCmdTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = baseDir
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		// The command itself is not included in the error as it may
+		// contain sensitive arguments.
+		return "", fmt.Errorf(
+			"command referenced in config failed: %w: %s",
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func expandPath(path string, baseDir string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(homeDir, path[1:]), nil
+	}
+
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return filepath.Join(baseDir, path), nil
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func hasReferences(value string) bool {
+	return referencePattern.MatchString(value)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterpolatorTrustedFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api-key"), []byte("secret-key\n"), 0644); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+	t.Setenv("CELERITY_TEST_ENDPOINT", "http://localhost:9000")
+
+	userConfigFile := filepath.Join(dir, "user.config.toml")
+	projectConfigFile := filepath.Join(dir, "celerity.config.toml")
+
+	tests := []struct {
+		name       string
+		value      string
+		sourcePath string
+		allowAll   bool
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "file reference in a trusted file",
+			value:      "${file:api-key}",
+			sourcePath: userConfigFile,
+			want:       "secret-key",
+		},
+		{
+			name:       "file reference in a file that is not trusted",
+			value:      "${file:api-key}",
+			sourcePath: projectConfigFile,
+			wantErr:    `"file" reference in "` + projectConfigFile + `" was refused`,
+		},
+		{
+			name:       "command reference in a file that is not trusted",
+			value:      "${cmd:echo secret-key}",
+			sourcePath: projectConfigFile,
+			wantErr:    `"cmd" reference in "` + projectConfigFile + `" was refused`,
+		},
+		{
+			name:       "file reference when all references are allowed",
+			value:      "${file:api-key}",
+			sourcePath: projectConfigFile,
+			allowAll:   true,
+			want:       "secret-key",
+		},
+		{
+			name:       "env reference in a file that is not trusted",
+			value:      "${env:CELERITY_TEST_ENDPOINT}",
+			sourcePath: projectConfigFile,
+			want:       "http://localhost:9000",
+		},
+		{
+			name:       "escaped reference in a file that is not trusted",
+			value:      "$${cmd:echo secret-key}",
+			sourcePath: projectConfigFile,
+			want:       "${cmd:echo secret-key}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interpolator := NewInterpolator(NewRedactor())
+			interpolator.TrustFile(userConfigFile)
+			if test.allowAll {
+				interpolator.AllowAllReferences()
+			}
+
+			got, err := interpolator.InterpolateString(test.value, test.sourcePath)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
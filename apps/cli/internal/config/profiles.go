@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ProfileConfigName is the name of the config value that holds
+	// the name of the active profile.
+	ProfileConfigName = "profile"
+
+	profilesSection = "profiles"
+)
+
+// ActiveProfile returns the name of the profile that is being used
+// to source config values, this will be empty if no profile is active.
+// The active profile is selected with the --profile flag,
+// the CELERITY_CLI_PROFILE environment variable or the "profile"
+// config value in a config file.
+func (p *Provider) ActiveProfile() string {
+	profile, _ := p.GetString(ProfileConfigName)
+	return profile
+}
+
+// Profiles returns the names of all the profiles
+// defined in the loaded config file.
+func (p *Provider) Profiles() []string {
+	profiles, _ := toMap(p.config[profilesSection])
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Profile returns all the config values defined for a profile
+// as a map of hierarchical config names to values.
+func (p *Provider) Profile(name string) (map[string]string, bool) {
+	profile, hasProfile := p.profileConfig(name)
+	if !hasProfile {
+		return nil, false
+	}
+
+	return flatten(profile, ""), true
+}
+
+// CheckActiveProfile makes sure the active profile, if one has been
+// selected, is defined in the loaded config file.
+func (p *Provider) CheckActiveProfile() error {
+	profile := p.ActiveProfile()
+	if profile == "" {
+		return nil
+	}
+
+	if _, hasProfile := p.profileConfig(profile); !hasProfile {
+		return fmt.Errorf(
+			"profile %q is not defined in the config file, available profiles: %s",
+			profile,
+			availableProfiles(p.Profiles()),
+		)
+	}
+
+	return nil
+}
+
+func (p *Provider) profileConfig(name string) (map[string]any, bool) {
+	return profileSection(p.config, name)
+}
+
+func profileSection(config map[string]any, name string) (map[string]any, bool) {
+	profiles, hasProfiles := toMap(config[profilesSection])
+	if !hasProfiles {
+		return nil, false
+	}
+
+	return toMap(profiles[name])
+}
+
+func flatten(config map[string]any, prefix string) map[string]string {
+	flattened := map[string]string{}
+	for key, value := range config {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+
+		if section, isSection := toMap(value); isSection {
+			for nestedKey, nestedValue := range flatten(section, fullKey) {
+				flattened[nestedKey] = nestedValue
+			}
+			continue
+		}
+
+		if strValue, isScalar := scalarToString(value); isScalar {
+			flattened[fullKey] = strValue
+			continue
+		}
+
+		if list, isList := toStringSlice(value); isList {
+			flattened[fullKey] = strings.Join(list, ",")
+		}
+	}
+
+	return flattened
+}
+
+func availableProfiles(profiles []string) string {
+	if len(profiles) == 0 {
+		return "(none)"
+	}
+
+	return strings.Join(profiles, ", ")
+}
@@ -1,15 +1,13 @@
 package config
 
 import (
-	"encoding/json"
 	"errors"
-	"os"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
-	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v3"
 )
 
 // Provider is a simple config provider for the CLI that can fall back to
@@ -21,52 +19,191 @@ import (
 // The precendence of config values is as follows:
 // 1. Flags
 // 2. Environment variables
-// 3. Config file
-// 4. Flag defaults
-// 5. Config provider defaults
+// 3. Active profile in the config file
+// 4. Config file
+// 5. Flag defaults
+// 6. Config provider defaults
 //
-// Provider only supports strings, booleans, integers and floats
-// as configuration values. Complex types such as arrays, structs and maps
-// are not supported.
+// Config names are hierarchical where each segment is separated by a ".",
+// for example, "validate.blueprintFile" will be sourced from the
+// "blueprintFile" field of the "validate" section in a config file.
+// A config file can also use the full config name as a single key.
+//
+// Provider supports strings, booleans, integers and floats as scalar
+// configuration values along with lists and maps of scalar values.
+// Lists and maps are provided as comma-separated values in environment variables
+// (e.g. "a,b,c" and "key1=value1,key2=value2").
+//
+// String values in config files can contain "${env:NAME}", "${file:path}"
+// and "${cmd:command}" references that are resolved lazily when a config value is
+// looked up, so secrets do not need to be committed to config files.
+// Secrets resolved by the provider are tracked by its Redactor.
+// File and command references are only resolved from trusted config files,
+// see LoadConfig.
+//
+// Config values supported by the CLI are declared with Register,
+// CheckConfig reports unknown config values and values of the wrong type.
 //
 // YAML, JSON and TOML are supported as config file formats.
+// Config files are layered, see LoadConfig for how config files
+// are discovered and merged.
 type Provider struct {
-	config   map[string]string
-	pFlags   map[string]*pflag.Flag
-	envVars  map[string]string
-	defaults map[string]string
+	config         map[string]any
+	configLayers   []*configLayer
+	configFilePath string
+	pFlags         map[string]*pflag.Flag
+	envVars        map[string]string
+	defaults       map[string]string
+	redactor       *Redactor
+	interpolator   *Interpolator
+	valueErrs      map[string]error
+	keys           map[string]Key
+	warnings       []string
 }
 
 // NewProvider creates a new Provider of configuration
 // values for the CLI.
 func NewProvider() *Provider {
+	redactor := NewRedactor()
 	return &Provider{
-		config:  map[string]string{},
-		pFlags:  map[string]*pflag.Flag{},
-		envVars: map[string]string{},
+		config:       map[string]any{},
+		pFlags:       map[string]*pflag.Flag{},
+		envVars:      map[string]string{},
+		defaults:     map[string]string{},
+		redactor:     redactor,
+		interpolator: NewInterpolator(redactor),
+		valueErrs:    map[string]error{},
+		keys:         map[string]Key{},
 	}
 }
 
-func (p *Provider) LoadConfigFile(configFilePath string) error {
-	configFile, err := os.Open(configFilePath)
+// LoadConfig discovers and loads the config files for the CLI.
+// Config files are merged in the following order, where values in later
+// config files take precedence over values in earlier config files:
+// 1. The user-level config file in the user config directory (see UserConfigDir)
+// 2. Config files found by walking up from the working directory to the project root,
+// starting from the project root (see DiscoverProjectConfigFiles)
+//
+// When a config file path is provided, it will be used in place of the
+// config files discovered in the project.
+// A missing user-level config file is treated as empty, a config file path
+// that has been provided must exist, otherwise an error that wraps
+// ErrConfigFileNotFound is returned after the user-level config file
+// has been loaded so commands that create the config file can carry on.
+//
+// File and command references are only resolved from the user-level config file
+// and the config file that has been provided as they can not be added by a project,
+// see Interpolator for how this can be changed.
+func (p *Provider) LoadConfig(configFilePath string) error {
+	configFilePaths := []string{}
+	userConfigFilePath, err := UserConfigFilePath()
+	// The user-level config file is optional, it is skipped when the
+	// user config directory can not be determined for the current environment.
+	if err == nil {
+		configFilePaths = append(configFilePaths, userConfigFilePath)
+		p.interpolator.TrustFile(userConfigFilePath)
+	}
+
+	var notFoundErr error
+	if configFilePath != "" {
+		exists, err := pathExists(configFilePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			notFoundErr = fmt.Errorf("%w: %s", ErrConfigFileNotFound, configFilePath)
+		}
+		configFilePaths = append(configFilePaths, configFilePath)
+		p.interpolator.TrustFile(configFilePath)
+	} else {
+		projectConfigFilePaths, err := DiscoverProjectConfigFiles(".")
+		if err != nil {
+			return err
+		}
+		configFilePaths = append(configFilePaths, projectConfigFilePaths...)
+	}
+
+	err = p.LoadConfigFiles(configFilePaths...)
 	if err != nil {
 		return err
 	}
 
-	if strings.HasSuffix(configFilePath, ".yaml") || strings.HasSuffix(configFilePath, ".yml") {
-		return yaml.NewDecoder(configFile).Decode(&p.config)
+	p.configFilePath = writableConfigFilePath(configFilePath, userConfigFilePath, configFilePaths)
+	return notFoundErr
+}
+
+// LoadConfigFiles loads config values from YAML, JSON or TOML files,
+// the format of each file is determined by the file extension.
+// Sections in config files are merged where values in later config files
+// take precedence over values in earlier config files,
+// lists are replaced rather than merged.
+// Missing config files are treated as empty.
+func (p *Provider) LoadConfigFiles(configFilePaths ...string) error {
+	config := map[string]any{}
+	configLayers := []*configLayer{}
+	p.warnings = nil
+	for _, configFilePath := range configFilePaths {
+		layer, err := loadConfigLayer(configFilePath)
+		if err != nil {
+			return err
+		}
+
+		if layer != nil {
+			p.resolveAliases(layer)
+			mergeConfig(config, layer.config)
+			configLayers = append(configLayers, layer)
+		}
 	}
 
-	if strings.HasSuffix(configFilePath, ".json") {
-		return json.NewDecoder(configFile).Decode(&p.config)
+	p.config = config
+	p.configLayers = configLayers
+	if len(configFilePaths) > 0 {
+		p.configFilePath = configFilePaths[len(configFilePaths)-1]
 	}
+	return nil
+}
 
-	if strings.HasSuffix(configFilePath, ".toml") {
-		_, err := toml.NewDecoder(configFile).Decode(&p.config)
-		return err
+// ConfigFilePath returns the path of the config file that changes
+// to config values should be written to, this is the config file
+// provided with the --config flag, the project config file closest to the
+// working directory or a new config file in the working directory.
+func (p *Provider) ConfigFilePath() string {
+	return p.configFilePath
+}
+
+// LoadedConfigFiles returns the paths of the config files that
+// were loaded by the provider in order of precedence,
+// where the last config file takes precedence.
+func (p *Provider) LoadedConfigFiles() []string {
+	paths := make([]string, 0, len(p.configLayers))
+	for _, layer := range p.configLayers {
+		paths = append(paths, layer.path)
 	}
+	return paths
+}
+
+// Redactor returns the redactor that tracks the secrets
+// resolved by the provider.
+func (p *Provider) Redactor() *Redactor {
+	return p.redactor
+}
+
+// Interpolator returns the interpolator used to resolve references
+// in config values, this can be used to resolve references in other
+// files that are sourced from config values such as the deploy config file.
+func (p *Provider) Interpolator() *Interpolator {
+	return p.interpolator
+}
 
-	return ErrUnsupportedConfigFileFormat
+// ValueErrors returns the errors for config values that have been looked up
+// where references could not be resolved or the value could not be parsed
+// as the requested type, nil is returned if there were no errors.
+func (p *Provider) ValueErrors() error {
+	errs := []error{}
+	for _, name := range slices.Sorted(maps.Keys(p.valueErrs)) {
+		errs = append(errs, fmt.Errorf("config value %q: %w", name, p.valueErrs[name]))
+	}
+	return errors.Join(errs...)
 }
 
 func (p *Provider) BindEnvVar(configName string, envVarName string) {
@@ -84,142 +221,113 @@ func (p *Provider) SetDefault(configName, value string) {
 // GetString returns the value of a configuration value as a string.
 // It also returns a boolean indicating whether the value was set by the user
 // or if it's a default value. `true` means the value is a default value.
+// Use Lookup to find out exactly where the value was sourced from.
 func (p *Provider) GetString(configName string) (string, bool) {
-	flag, hasFlag := p.pFlags[configName]
-	defaultFlagValue := ""
-	if hasFlag {
-		value := flag.Value.String()
-		if strings.TrimSpace(value) != "" {
-			if flag.Changed {
-				// Flag set by user.
-				return value, false
-			} else {
-				// Flag not set by user, fallback to default value.
-				defaultFlagValue = value
-			}
-		}
-	}
-
-	envVarName, hasEnvVarName := p.envVars[configName]
-	if hasEnvVarName {
-		envVar, envVarExists := os.LookupEnv(envVarName)
-		if envVarExists && strings.TrimSpace(envVar) != "" {
-			return envVar, false
-		}
-	}
-
-	configValue, hasConfigValue := p.config[configName]
-	if hasConfigValue {
-		return configValue, false
-	}
-
-	if defaultFlagValue != "" {
-		return defaultFlagValue, true
-	}
-
-	return p.defaults[configName], true
+	value, source := p.Lookup(configName)
+	return value, source.IsDefault()
 }
 
+// GetInt32 returns the value of a configuration value as a 32-bit integer.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetInt32(configName string) (int32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
-	}
-
-	intVal, err := strconv.ParseInt(strVal, 10, 32)
-	if err != nil {
-		return 0, isDefault
-	}
-
+	p.checkType(configName, ValueTypeInt)
+	intVal, isDefault := getParsed(p, configName, ValueTypeInt, func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 32)
+	})
 	return int32(intVal), isDefault
 }
 
+// GetInt64 returns the value of a configuration value as a 64-bit integer.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetInt64(configName string) (int64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
-	}
-
-	intVal, err := strconv.ParseInt(strVal, 10, 64)
-	if err != nil {
-		return 0, isDefault
-	}
-
-	return intVal, isDefault
+	p.checkType(configName, ValueTypeInt)
+	return getParsed(p, configName, ValueTypeInt, func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
 }
 
+// GetUint32 returns the value of a configuration value as an unsigned 32-bit integer.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetUint32(configName string) (uint32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
-	}
-
-	intVal, err := strconv.ParseUint(strVal, 10, 32)
-	if err != nil {
-		return 0, isDefault
-	}
-
+	p.checkType(configName, ValueTypeInt)
+	intVal, isDefault := getParsed(p, configName, ValueTypeInt, func(value string) (uint64, error) {
+		return strconv.ParseUint(value, 10, 32)
+	})
 	return uint32(intVal), isDefault
 }
 
+// GetUint64 returns the value of a configuration value as an unsigned 64-bit integer.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetUint64(configName string) (uint64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0, isDefault
-	}
-
-	intVal, err := strconv.ParseUint(strVal, 10, 64)
-	if err != nil {
-		return 0, isDefault
-	}
-
-	return intVal, isDefault
+	p.checkType(configName, ValueTypeInt)
+	return getParsed(p, configName, ValueTypeInt, func(value string) (uint64, error) {
+		return strconv.ParseUint(value, 10, 64)
+	})
 }
 
+// GetFloat32 returns the value of a configuration value as a 32-bit float.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetFloat32(configName string) (float32, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0.0, isDefault
-	}
-
-	floatVal, err := strconv.ParseFloat(strVal, 32)
-	if err != nil {
-		return 0.0, isDefault
-	}
-
+	p.checkType(configName, ValueTypeFloat, ValueTypeInt)
+	floatVal, isDefault := getParsed(p, configName, ValueTypeFloat, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 32)
+	})
 	return float32(floatVal), isDefault
 }
 
+// GetFloat64 returns the value of a configuration value as a 64-bit float.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and the zero value is returned.
 func (p *Provider) GetFloat64(configName string) (float64, bool) {
-	strVal, isDefault := p.GetString(configName)
-	if strVal == "" {
-		return 0.0, isDefault
-	}
-
-	floatVal, err := strconv.ParseFloat(strVal, 64)
-	if err != nil {
-		return 0.0, isDefault
-	}
-
-	return floatVal, isDefault
+	p.checkType(configName, ValueTypeFloat, ValueTypeInt)
+	return getParsed(p, configName, ValueTypeFloat, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
 }
 
+// GetBool returns the value of a configuration value as a boolean.
+// Values that can not be parsed are recorded as value errors (see ValueErrors)
+// and false is returned.
 func (p *Provider) GetBool(configName string) (bool, bool) {
+	p.checkType(configName, ValueTypeBool)
+	return getParsed(p, configName, ValueTypeBool, strconv.ParseBool)
+}
+
+func getParsed[Value any](
+	p *Provider,
+	configName string,
+	valueType ValueType,
+	parse func(string) (Value, error),
+) (Value, bool) {
+	var zero Value
 	strVal, isDefault := p.GetString(configName)
 	if strVal == "" {
-		return false, isDefault
+		return zero, isDefault
 	}
 
-	boolVal, err := strconv.ParseBool(strVal)
+	parsed, err := parse(strVal)
 	if err != nil {
-		return false, isDefault
+		p.valueErrs[configName] = fmt.Errorf(
+			"expected a value of type %s, received %q",
+			typeDescription(valueType),
+			strVal,
+		)
+		return zero, isDefault
 	}
 
-	return boolVal, isDefault
+	return parsed, isDefault
 }
 
 var (
 	// ErrUnsupportedConfigFileFormat is returned when the config file format
 	// is not supported by the provider.
 	ErrUnsupportedConfigFileFormat = errors.New("unsupported config file format, only yaml, json and toml are supported")
+	// ErrConfigFileNotFound is returned when a config file that has been
+	// explicitly provided does not exist.
+	ErrConfigFileNotFound = errors.New("config file does not exist")
 )
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+
+	// Very short values are not redacted to avoid mangling
+	// unrelated output.
+	minRedactedLength = 4
+)
+
+// Redactor tracks secrets resolved by the config provider
+// so they can be removed from logs and output written to the terminal.
+type Redactor struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// NewRedactor creates a new redactor with no tracked secrets.
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// Add tracks a secret to be redacted.
+func (r *Redactor) Add(secret string) {
+	if len(secret) < minRedactedLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.Contains(r.secrets, secret) {
+		return
+	}
+
+	r.secrets = append(r.secrets, secret)
+	// Longer secrets are replaced first so secrets that contain
+	// other secrets are fully redacted.
+	slices.SortFunc(r.secrets, func(a, b string) int {
+		return len(b) - len(a)
+	})
+}
+
+// Redact replaces all the tracked secrets in the provided string.
+func (r *Redactor) Redact(value string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		value = strings.ReplaceAll(value, secret, redactedValue)
+	}
+	return value
+}
+
+// Writer wraps the provided writer to redact tracked secrets
+// from everything that is written to it.
+func (r *Redactor) Writer(writer io.Writer) io.Writer {
+	return &redactingWriter{writer: writer, redactor: r}
+}
+
+// File wraps a file to redact tracked secrets from everything
+// that is written to it, this keeps the file descriptor available
+// so the wrapped file can still be detected as a terminal.
+func (r *Redactor) File(file *os.File) *RedactingFile {
+	return &RedactingFile{File: file, redactor: r}
+}
+
+// RedactingFile is a file that redacts tracked secrets
+// from everything that is written to it.
+type RedactingFile struct {
+	*os.File
+	redactor *Redactor
+}
+
+func (f *RedactingFile) Write(data []byte) (int, error) {
+	return writeRedacted(f.File, f.redactor, data)
+}
+
+type redactingWriter struct {
+	writer   io.Writer
+	redactor *Redactor
+}
+
+func (w *redactingWriter) Write(data []byte) (int, error) {
+	return writeRedacted(w.writer, w.redactor, data)
+}
+
+func writeRedacted(writer io.Writer, redactor *Redactor, data []byte) (int, error) {
+	_, err := io.WriteString(writer, redactor.Redact(string(data)))
+	if err != nil {
+		return 0, err
+	}
+
+	// The length of the original data is reported as written
+	// as callers expect all of the provided bytes to be consumed.
+	return len(data), nil
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ValueType is the type of a registered config value.
+type ValueType string
+
+const (
+	// ValueTypeString is used for string config values.
+	ValueTypeString ValueType = "string"
+	// ValueTypeBool is used for boolean config values.
+	ValueTypeBool ValueType = "bool"
+	// ValueTypeInt is used for integer config values.
+	ValueTypeInt ValueType = "int"
+	// ValueTypeFloat is used for floating point config values.
+	ValueTypeFloat ValueType = "float"
+	// ValueTypeStringList is used for config values that hold a list of strings.
+	ValueTypeStringList ValueType = "stringList"
+	// ValueTypeStringMap is used for config values that hold a map of strings.
+	ValueTypeStringMap ValueType = "stringMap"
+)
+
+// Key declares a config value that is supported by the CLI.
+type Key struct {
+	// Name is the hierarchical name of the config value,
+	// e.g. "validate.blueprintFile".
+	Name string
+	Type ValueType
+	// Default is the default value in the same string form
+	// that is used for environment variables.
+	Default string
+	// EnvVar is the name of the environment variable that the config value
+	// can be sourced from, this is optional.
+	EnvVar string
+	// Flag is the name of the flag that can be used to set the config value,
+	// this is optional and the flag is only added to commands that
+	// call Provider.AddFlags for the config value.
+	Flag      string
+	Shorthand string
+	// Description is used for the flag usage and the JSON schema
+	// for config files.
+	Description string
+	// Enum is an optional list of the allowed values for a string config value.
+	Enum []string
+	// Aliases are deprecated names for the config value that are still
+	// accepted in config files, values set with an alias are moved
+	// to the current name of the config value when config files are loaded.
+	Aliases []string
+}
+
+// Register adds config values to the provider's registry,
+// default values are set and environment variables are bound
+// for each of the provided keys.
+func (p *Provider) Register(keys ...Key) {
+	for _, key := range keys {
+		p.keys[key.Name] = key
+		if key.Default != "" {
+			p.SetDefault(key.Name, key.Default)
+		}
+		if key.EnvVar != "" {
+			p.BindEnvVar(key.Name, key.EnvVar)
+		}
+	}
+}
+
+// Keys returns all the registered config values sorted by name.
+func (p *Provider) Keys() []Key {
+	keys := make([]Key, 0, len(p.keys))
+	for _, name := range slices.Sorted(maps.Keys(p.keys)) {
+		keys = append(keys, p.keys[name])
+	}
+	return keys
+}
+
+// Key returns the registered config value with the provided name.
+func (p *Provider) Key(configName string) (Key, bool) {
+	key, hasKey := p.keys[configName]
+	return key, hasKey
+}
+
+// AddFlags adds flags to the provided flag set for registered config values
+// and binds them to the config values.
+// This panics if a config value is not registered or does not declare a flag
+// as this is a mistake in how the CLI is set up.
+func (p *Provider) AddFlags(flagSet *pflag.FlagSet, configNames ...string) {
+	for _, configName := range configNames {
+		key, hasKey := p.keys[configName]
+		if !hasKey || key.Flag == "" {
+			panic(fmt.Sprintf("config value %q is not registered with a flag", configName))
+		}
+
+		switch key.Type {
+		case ValueTypeBool:
+			defaultValue, _ := strconv.ParseBool(key.Default)
+			flagSet.BoolP(key.Flag, key.Shorthand, defaultValue, key.Description)
+		case ValueTypeInt:
+			defaultValue, _ := strconv.ParseInt(key.Default, 10, 64)
+			flagSet.Int64P(key.Flag, key.Shorthand, defaultValue, key.Description)
+		case ValueTypeFloat:
+			defaultValue, _ := strconv.ParseFloat(key.Default, 64)
+			flagSet.Float64P(key.Flag, key.Shorthand, defaultValue, key.Description)
+		case ValueTypeStringList:
+			flagSet.StringSliceP(key.Flag, key.Shorthand, splitList(key.Default), key.Description)
+		case ValueTypeStringMap:
+			flagSet.VarP(newMapFlagValue(splitList(key.Default)), key.Flag, key.Shorthand, key.Description)
+		default:
+			flagSet.StringP(key.Flag, key.Shorthand, key.Default, key.Description)
+		}
+
+		p.BindPFlag(configName, flagSet.Lookup(key.Flag))
+	}
+}
+
+// checkType makes sure that a typed getter is only used for config
+// values registered with the expected type.
+// This panics on a mismatch as this is a mistake in how the CLI is set up.
+func (p *Provider) checkType(configName string, valueTypes ...ValueType) {
+	key, hasKey := p.keys[configName]
+	if hasKey && !slices.Contains(valueTypes, key.Type) {
+		panic(fmt.Sprintf(
+			"config value %q is registered as %s, can not be retrieved as %s",
+			configName,
+			key.Type,
+			valueTypes[0],
+		))
+	}
+}
+
+// checkValue makes sure a config value can be parsed
+// as the registered type.
+func checkValue(key Key, value any) error {
+	switch key.Type {
+	case ValueTypeBool:
+		return checkScalar(key, value, func(strValue string) error {
+			_, err := strconv.ParseBool(strValue)
+			return err
+		})
+	case ValueTypeInt:
+		return checkScalar(key, value, func(strValue string) error {
+			_, err := strconv.ParseInt(strValue, 10, 64)
+			return err
+		})
+	case ValueTypeFloat:
+		return checkScalar(key, value, func(strValue string) error {
+			_, err := strconv.ParseFloat(strValue, 64)
+			return err
+		})
+	case ValueTypeStringList:
+		if _, isList := toStringSlice(value); !isList {
+			return errInvalidType(key)
+		}
+		return nil
+	case ValueTypeStringMap:
+		if _, isMap := toStringMap(value); !isMap {
+			if strValue, isString := value.(string); !isString || !isMapString(strValue) {
+				return errInvalidType(key)
+			}
+		}
+		return nil
+	default:
+		return checkScalar(key, value, func(strValue string) error {
+			if len(key.Enum) > 0 && !slices.Contains(key.Enum, strValue) {
+				return fmt.Errorf("must be one of %s", quoteAll(key.Enum))
+			}
+			return nil
+		})
+	}
+}
+
+func checkScalar(key Key, value any, check func(string) error) error {
+	strValue, isScalar := scalarToString(value)
+	if !isScalar {
+		return errInvalidType(key)
+	}
+
+	// Values that contain references can only be checked
+	// once the references have been resolved.
+	if hasReferences(strValue) {
+		return nil
+	}
+
+	if err := check(strValue); err != nil {
+		if key.Type == ValueTypeString {
+			return err
+		}
+		return errInvalidType(key)
+	}
+
+	return nil
+}
+
+func isMapString(value string) bool {
+	for _, item := range splitList(value) {
+		if !strings.Contains(item, "=") {
+			return false
+		}
+	}
+	return true
+}
+
+func errInvalidType(key Key) error {
+	return fmt.Errorf("expected a value of type %s", typeDescription(key.Type))
+}
+
+func typeDescription(valueType ValueType) string {
+	switch valueType {
+	case ValueTypeBool:
+		return "boolean"
+	case ValueTypeInt:
+		return "integer"
+	case ValueTypeFloat:
+		return "number"
+	case ValueTypeStringList:
+		return "list of strings"
+	case ValueTypeStringMap:
+		return "map of strings"
+	}
+
+	return "string"
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// JSONSchema creates a JSON Schema for config files from the registered
+// config values, this can be used by editors to provide completion
+// and validation for config files.
+// Config values are nested in sections for each segment of their names
+// and the "profiles" section allows any registered config value
+// other than the active profile in each named profile.
+func (p *Provider) JSONSchema() map[string]any {
+	schema := objectSchema("")
+	profileSchema := objectSchema("A named profile that holds config values that take precedence over the top level of config files.")
+	for _, key := range p.Keys() {
+		addToSchema(schema, key.Name, keySchema(key))
+		if key.Name != ProfileConfigName {
+			addToSchema(profileSchema, key.Name, keySchema(key))
+		}
+		for _, alias := range key.Aliases {
+			addToSchema(schema, alias, aliasSchema(key))
+			addToSchema(profileSchema, alias, aliasSchema(key))
+		}
+	}
+
+	schemaProperties(schema)[profilesSection] = map[string]any{
+		"type":                 "object",
+		"description":          "Named profiles that can be activated with the \"profile\" config value.",
+		"additionalProperties": profileSchema,
+	}
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = "Celerity CLI config"
+	return schema
+}
+
+func addToSchema(schema map[string]any, configName string, valueSchema map[string]any) {
+	segments := strings.Split(configName, ".")
+	section := schema
+	for _, segment := range segments[:len(segments)-1] {
+		properties := schemaProperties(section)
+		nested, hasNested := properties[segment].(map[string]any)
+		if !hasNested {
+			nested = objectSchema("")
+			properties[segment] = nested
+		}
+		section = nested
+	}
+
+	schemaProperties(section)[segments[len(segments)-1]] = valueSchema
+}
+
+func objectSchema(description string) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{},
+		"additionalProperties": false,
+	}
+	if description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
+
+func schemaProperties(schema map[string]any) map[string]any {
+	return schema["properties"].(map[string]any)
+}
+
+func keySchema(key Key) map[string]any {
+	schema := map[string]any{}
+	if key.Description != "" {
+		schema["description"] = key.Description
+	}
+
+	switch key.Type {
+	case ValueTypeBool:
+		schema["type"] = "boolean"
+		if defaultValue, err := strconv.ParseBool(key.Default); err == nil {
+			schema["default"] = defaultValue
+		}
+	case ValueTypeInt:
+		schema["type"] = "integer"
+		if defaultValue, err := strconv.ParseInt(key.Default, 10, 64); err == nil {
+			schema["default"] = defaultValue
+		}
+	case ValueTypeFloat:
+		schema["type"] = "number"
+		if defaultValue, err := strconv.ParseFloat(key.Default, 64); err == nil {
+			schema["default"] = defaultValue
+		}
+	case ValueTypeStringList:
+		// A single string is treated as a comma-separated list.
+		schema["type"] = []string{"array", "string"}
+		schema["items"] = map[string]any{"type": "string"}
+		if key.Default != "" {
+			schema["default"] = splitList(key.Default)
+		}
+	case ValueTypeStringMap:
+		schema["type"] = "object"
+		schema["additionalProperties"] = map[string]any{
+			"type": []string{"string", "number", "boolean"},
+		}
+	default:
+		schema["type"] = "string"
+		if key.Default != "" {
+			schema["default"] = key.Default
+		}
+		if len(key.Enum) > 0 {
+			schema["enum"] = key.Enum
+		}
+	}
+
+	return schema
+}
+
+// aliasSchema creates the schema for a deprecated name of a config value
+// so existing config files that use it are not reported as invalid.
+func aliasSchema(key Key) map[string]any {
+	schema := keySchema(key)
+	schema["description"] = fmt.Sprintf("Deprecated, use %q instead.", key.Name)
+	schema["deprecated"] = true
+	return schema
+}
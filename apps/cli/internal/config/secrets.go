@@ -0,0 +1,35 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+const maskedValue = "********"
+
+var (
+	secretFieldNames = []string{
+		"apikey",
+		"clientsecret",
+		"secretkey",
+		"password",
+		"token",
+	}
+)
+
+// IsSecret determines whether a config value holds a secret
+// based on the last segment of its hierarchical config name.
+func IsSecret(configName string) bool {
+	segments := strings.Split(configName, ".")
+	return slices.Contains(secretFieldNames, strings.ToLower(segments[len(segments)-1]))
+}
+
+// MaskValue masks the value of a config value if it holds a secret
+// so it can be safely displayed to the user.
+func MaskValue(configName string, value string) string {
+	if value == "" || !IsSecret(configName) {
+		return value
+	}
+
+	return maskedValue
+}
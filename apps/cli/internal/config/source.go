@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// SourceKind is the kind of source that a config value was resolved from.
+type SourceKind string
+
+const (
+	// SourceFlag is used for values set by the user with a command line flag.
+	SourceFlag SourceKind = "flag"
+	// SourceEnvVar is used for values sourced from an environment variable.
+	SourceEnvVar SourceKind = "env"
+	// SourceProfile is used for values sourced from the active profile
+	// in the config file.
+	SourceProfile SourceKind = "profile"
+	// SourceConfigFile is used for values sourced from the top level
+	// of the config file.
+	SourceConfigFile SourceKind = "config file"
+	// SourceFlagDefault is used for values taken from the default value
+	// of a command line flag.
+	SourceFlagDefault SourceKind = "flag default"
+	// SourceDefault is used for values that fall back to the defaults
+	// set on the provider, this is also used for values that are not set at all.
+	SourceDefault SourceKind = "default"
+)
+
+// Source describes where a config value was resolved from.
+type Source struct {
+	Kind SourceKind
+	// Name holds the name of the flag, the environment variable
+	// or the profile that the value was sourced from.
+	// This is empty for values that are not sourced from a named source.
+	Name string
+	// ConfigFilePath is the path of the config file that the value was
+	// sourced from for values from the config file or a profile.
+	ConfigFilePath string
+}
+
+// IsDefault determines whether the value was not explicitly
+// set by the user.
+func (s Source) IsDefault() bool {
+	return s.Kind == SourceFlagDefault || s.Kind == SourceDefault
+}
+
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFlag, SourceFlagDefault:
+		return fmt.Sprintf("%s --%s", s.Kind, s.Name)
+	case SourceEnvVar:
+		return fmt.Sprintf("%s %s", s.Kind, s.Name)
+	case SourceProfile:
+		return fmt.Sprintf("%s %q (%s)", s.Kind, s.Name, s.ConfigFilePath)
+	case SourceConfigFile:
+		return fmt.Sprintf("%s %s", s.Kind, s.ConfigFilePath)
+	}
+
+	return string(s.Kind)
+}
+
+// Setting is an effective config value along with
+// the source it was resolved from.
+type Setting struct {
+	Name   string
+	Value  string
+	Source Source
+	// Err is set when the config value contains a reference
+	// that could not be resolved.
+	Err error
+}
+
+// Lookup resolves a configuration value as a string along with
+// the source that it was resolved from.
+// Lists and maps from a config file are provided in the same comma-separated
+// form that is used for environment variables.
+// Values of secret config names (see IsSecret) are added to the
+// provider's redactor.
+func (p *Provider) Lookup(configName string) (string, Source) {
+	value, source := p.lookup(configName)
+	if IsSecret(configName) {
+		p.redactor.Add(value)
+	}
+	return value, source
+}
+
+func (p *Provider) lookup(configName string) (string, Source) {
+	flag, hasFlag := p.pFlags[configName]
+	defaultFlagValue := ""
+	if hasFlag {
+		value := flagStringValue(flag)
+		if strings.TrimSpace(value) != "" {
+			if flag.Changed {
+				// Flag set by user.
+				return value, Source{Kind: SourceFlag, Name: flag.Name}
+			} else {
+				// Flag not set by user, fallback to default value.
+				defaultFlagValue = value
+			}
+		}
+	}
+
+	envVarValue, hasEnvVar := p.envVarValue(configName)
+	if hasEnvVar {
+		return envVarValue, Source{Kind: SourceEnvVar, Name: p.envVars[configName]}
+	}
+
+	configValue, source, hasConfigValue := p.configValueWithSource(configName)
+	if hasConfigValue {
+		if strValue, isValue := configValueToString(configValue); isValue {
+			return strValue, source
+		}
+	}
+
+	if defaultFlagValue != "" {
+		return defaultFlagValue, Source{Kind: SourceFlagDefault, Name: flag.Name}
+	}
+
+	return p.defaults[configName], Source{Kind: SourceDefault}
+}
+
+// Settings returns every effective config value known to the provider,
+// this includes values bound to flags and environment variables,
+// provider defaults and values set in the config file or the active profile.
+// Settings are sorted by config name.
+func (p *Provider) Settings() []Setting {
+	names := map[string]struct{}{}
+	for _, source := range []map[string]string{
+		p.envVars,
+		p.defaults,
+		flatten(p.configWithoutProfiles(), ""),
+	} {
+		for name := range source {
+			names[name] = struct{}{}
+		}
+	}
+
+	for name := range p.pFlags {
+		names[name] = struct{}{}
+	}
+
+	if profile, hasProfile := p.profileConfig(p.ActiveProfile()); hasProfile {
+		for name := range flatten(profile, "") {
+			names[name] = struct{}{}
+		}
+	}
+
+	settings := make([]Setting, 0, len(names))
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		value, source := p.Lookup(name)
+		settings = append(settings, Setting{
+			Name:   name,
+			Value:  value,
+			Source: source,
+			Err:    p.valueErrs[name],
+		})
+	}
+
+	return settings
+}
+
+func (p *Provider) configWithoutProfiles() map[string]any {
+	config := maps.Clone(p.config)
+	delete(config, profilesSection)
+	return config
+}
+
+func flagStringValue(flag *pflag.Flag) string {
+	if _, isSlice := flag.Value.(pflag.SliceValue); isSlice {
+		return strings.Join(flagSliceValue(flag), ",")
+	}
+
+	return flag.Value.String()
+}
+
+func configValueToString(value any) (string, bool) {
+	if strValue, isScalar := scalarToString(value); isScalar {
+		return strValue, true
+	}
+
+	if list, isList := toStringSlice(value); isList {
+		return strings.Join(list, ","), true
+	}
+
+	if stringMap, isMap := toStringMap(value); isMap {
+		pairs := make([]string, 0, len(stringMap))
+		for _, key := range slices.Sorted(maps.Keys(stringMap)) {
+			pairs = append(pairs, key+"="+stringMap[key])
+		}
+		return strings.Join(pairs, ","), true
+	}
+
+	return "", false
+}
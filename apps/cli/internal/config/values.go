@@ -0,0 +1,293 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// GetStringSlice returns the value of a configuration value as a list of strings.
+// Lists can be provided as string slice flags, comma-separated environment variables
+// or lists of scalar values in a config file.
+// A single string in a config file will be treated as a comma-separated list.
+// It also returns a boolean indicating whether the value was set by the user
+// or if it's a default value. `true` means the value is a default value.
+func (p *Provider) GetStringSlice(configName string) ([]string, bool) {
+	p.checkType(configName, ValueTypeStringList)
+	flag, hasFlag := p.pFlags[configName]
+	if hasFlag && flag.Changed {
+		return flagSliceValue(flag), false
+	}
+
+	envVarValue, hasEnvVar := p.envVarValue(configName)
+	if hasEnvVar {
+		return splitList(envVarValue), false
+	}
+
+	configValue, hasConfigValue := p.configValue(configName)
+	if hasConfigValue {
+		if list, isList := toStringSlice(configValue); isList {
+			return list, false
+		}
+	}
+
+	if hasFlag {
+		if defaultValue := flagSliceValue(flag); len(defaultValue) > 0 {
+			return defaultValue, true
+		}
+	}
+
+	return splitList(p.defaults[configName]), true
+}
+
+// GetStringMap returns the value of a configuration value as a map of strings.
+// Maps can be provided as flags and environment variables in the form "key1=value1,key2=value2"
+// or as tables/objects of scalar values in a config file.
+// It also returns a boolean indicating whether the value was set by the user
+// or if it's a default value. `true` means the value is a default value.
+func (p *Provider) GetStringMap(configName string) (map[string]string, bool) {
+	p.checkType(configName, ValueTypeStringMap)
+	flag, hasFlag := p.pFlags[configName]
+	if hasFlag && flag.Changed {
+		return flagMapValue(flag), false
+	}
+
+	envVarValue, hasEnvVar := p.envVarValue(configName)
+	if hasEnvVar {
+		return splitMap(envVarValue), false
+	}
+
+	configValue, hasConfigValue := p.configValue(configName)
+	if hasConfigValue {
+		if stringMap, isMap := toStringMap(configValue); isMap {
+			return stringMap, false
+		}
+	}
+
+	if hasFlag {
+		if defaultValue := flagMapValue(flag); len(defaultValue) > 0 {
+			return defaultValue, true
+		}
+	}
+
+	return splitMap(p.defaults[configName]), true
+}
+
+func (p *Provider) envVarValue(configName string) (string, bool) {
+	envVarName, hasEnvVarName := p.envVars[configName]
+	if !hasEnvVarName {
+		return "", false
+	}
+
+	envVar, envVarExists := os.LookupEnv(envVarName)
+	if !envVarExists || strings.TrimSpace(envVar) == "" {
+		return "", false
+	}
+
+	return envVar, true
+}
+
+// configValue looks up a value from the loaded config file,
+// values in the active profile take precedence over values
+// defined at the top level of the config file.
+func (p *Provider) configValue(configName string) (any, bool) {
+	value, _, hasValue := p.configValueWithSource(configName)
+	return value, hasValue
+}
+
+func (p *Provider) configValueWithSource(configName string) (any, Source, bool) {
+	value, source, hasValue := p.rawConfigValueWithSource(configName)
+	if !hasValue {
+		return nil, source, false
+	}
+
+	interpolated, err := p.interpolator.Interpolate(value, source.ConfigFilePath)
+	if err != nil {
+		p.valueErrs[configName] = err
+		return nil, source, false
+	}
+
+	return interpolated, source, true
+}
+
+func (p *Provider) rawConfigValueWithSource(configName string) (any, Source, bool) {
+	// The active profile is resolved from the config file as well,
+	// so it must not be looked up in the profile itself.
+	if configName != ProfileConfigName {
+		activeProfile := p.ActiveProfile()
+		if profile, hasProfile := p.profileConfig(activeProfile); hasProfile {
+			if value, hasValue := lookupValue(profile, configName); hasValue {
+				return value, Source{
+					Kind:           SourceProfile,
+					Name:           activeProfile,
+					ConfigFilePath: p.sourceConfigFile(activeProfile, configName),
+				}, true
+			}
+		}
+	}
+
+	value, hasValue := lookupValue(p.config, configName)
+	return value, Source{
+		Kind:           SourceConfigFile,
+		ConfigFilePath: p.sourceConfigFile("", configName),
+	}, hasValue
+}
+
+// sourceConfigFile finds the config file with the highest precedence
+// that defines a config value, when a profile is provided,
+// the value is looked up in the profile in each config file.
+func (p *Provider) sourceConfigFile(profile string, configName string) string {
+	for _, layer := range slices.Backward(p.configLayers) {
+		config := layer.config
+		if profile != "" {
+			config, _ = profileSection(layer.config, profile)
+		}
+
+		if _, hasValue := lookupValue(config, configName); hasValue {
+			return layer.path
+		}
+	}
+
+	return ""
+}
+
+// lookupValue looks up a value in a config section where a value
+// stored under the full config name takes precedence over
+// a value in nested sections.
+func lookupValue(config map[string]any, configName string) (any, bool) {
+	value, hasValue := config[configName]
+	if hasValue {
+		return value, true
+	}
+
+	var current any = config
+	for _, segment := range strings.Split(configName, ".") {
+		section, isSection := toMap(current)
+		if !isSection {
+			return nil, false
+		}
+
+		current, hasValue = section[segment]
+		if !hasValue {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func flagSliceValue(flag *pflag.Flag) []string {
+	sliceValue, isSlice := flag.Value.(pflag.SliceValue)
+	if isSlice {
+		return sliceValue.GetSlice()
+	}
+
+	return splitList(flag.Value.String())
+}
+
+func flagMapValue(flag *pflag.Flag) map[string]string {
+	stringMap := map[string]string{}
+	for _, item := range flagSliceValue(flag) {
+		key, mapValue, _ := strings.Cut(item, "=")
+		stringMap[strings.TrimSpace(key)] = mapValue
+	}
+	return stringMap
+}
+
+func toMap(value any) (map[string]any, bool) {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return typedValue, true
+	case map[any]any:
+		converted := make(map[string]any, len(typedValue))
+		for key, item := range typedValue {
+			converted[fmt.Sprintf("%v", key)] = item
+		}
+		return converted, true
+	}
+
+	return nil, false
+}
+
+func toStringSlice(value any) ([]string, bool) {
+	if strValue, isString := value.(string); isString {
+		return splitList(strValue), true
+	}
+
+	items, isList := value.([]any)
+	if !isList {
+		return nil, false
+	}
+
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		strItem, isScalar := scalarToString(item)
+		if !isScalar {
+			return nil, false
+		}
+		list = append(list, strItem)
+	}
+
+	return list, true
+}
+
+func toStringMap(value any) (map[string]string, bool) {
+	section, isMap := toMap(value)
+	if !isMap {
+		return nil, false
+	}
+
+	stringMap := make(map[string]string, len(section))
+	for key, item := range section {
+		strItem, isScalar := scalarToString(item)
+		if !isScalar {
+			return nil, false
+		}
+		stringMap[key] = strItem
+	}
+
+	return stringMap, true
+}
+
+func scalarToString(value any) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case int:
+		return strconv.Itoa(typedValue), true
+	case int64:
+		return strconv.FormatInt(typedValue, 10), true
+	case uint64:
+		return strconv.FormatUint(typedValue, 10), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	}
+
+	return "", false
+}
+
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			list = append(list, trimmed)
+		}
+	}
+	return list
+}
+
+func splitMap(value string) map[string]string {
+	stringMap := map[string]string{}
+	for _, item := range splitList(value) {
+		key, mapValue, _ := strings.Cut(item, "=")
+		stringMap[strings.TrimSpace(key)] = strings.TrimSpace(mapValue)
+	}
+	return stringMap
+}
@@ -0,0 +1,129 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"go.uber.org/zap"
+)
+
+// Options holds the user-provided options for deploying
+// a blueprint instance.
+type Options struct {
+	// BlueprintFile is the location of the blueprint file to deploy,
+	// this can be a local path or a URI for a remote source.
+	BlueprintFile string
+	// InstanceID is the ID of an existing blueprint instance to update.
+	InstanceID string
+	// InstanceName is the user-defined name of an existing
+	// blueprint instance to update.
+	InstanceName string
+	// ChangesetID is the ID of a change set that has already been staged,
+	// when this is empty, changes will be staged before deploying.
+	ChangesetID string
+	// DeployConfig is the config loaded from the deploy config file
+	// that is sent to the deploy engine, this is nil when there is
+	// no deploy config file.
+	DeployConfig *types.BlueprintOperationConfig
+	// RemoteEngine should be set when the deploy engine runs on
+	// a different machine and can not read local blueprint files.
+	RemoteEngine bool
+}
+
+// IsUpdate determines whether the options are for updating an
+// existing blueprint instance as opposed to deploying a new one.
+func (o *Options) IsUpdate() bool {
+	return o.InstanceID != "" || o.InstanceName != ""
+}
+
+// StageChanges produces the change set that will be used to deploy
+// the blueprint instance.
+// When a change set ID has been provided, the existing change set will be
+// retrieved from the deploy engine instead of staging new changes.
+func StageChanges(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	logger *zap.Logger,
+) (*stage.Result, error) {
+	if opts.ChangesetID == "" {
+		return stage.Run(
+			ctx,
+			deployEngine,
+			&stage.Options{
+				BlueprintFile: opts.BlueprintFile,
+				InstanceID:    opts.InstanceID,
+				InstanceName:  opts.InstanceName,
+				DeployConfig:  opts.DeployConfig,
+				RemoteEngine:  opts.RemoteEngine,
+			},
+			logger,
+		)
+	}
+
+	changeset, err := deployEngine.GetChangeset(ctx, opts.ChangesetID)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	return &stage.Result{
+		ChangesetID: changeset.ID,
+		InstanceID:  changeset.InstanceID,
+		Changes:     changeset.Changes,
+	}, nil
+}
+
+// Start starts the deployment of a blueprint instance with the provided
+// change set, creating a new instance or updating an existing one
+// depending on whether an instance ID or name has been provided.
+func Start(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	staged *stage.Result,
+	logger *zap.Logger,
+) (*state.InstanceState, error) {
+	docInfo, err := blueprint.ResolveDocumentInfo(opts.BlueprintFile, opts.RemoteEngine)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &types.BlueprintInstancePayload{
+		BlueprintDocumentInfo: docInfo,
+		ChangeSetID:           staged.ChangesetID,
+		Config:                opts.DeployConfig,
+	}
+
+	if !opts.IsUpdate() {
+		instance, err := deployEngine.CreateBlueprintInstance(ctx, payload)
+		if err != nil {
+			return nil, engine.SimplifyError(err, logger)
+		}
+		return instance, nil
+	}
+
+	instanceID := opts.InstanceID
+	if instanceID == "" {
+		// The deploy engine resolves the instance name to an ID
+		// when staging changes for an existing instance.
+		instanceID = staged.InstanceID
+	}
+
+	if instanceID == "" {
+		return nil, fmt.Errorf(
+			"could not resolve an instance ID for the blueprint instance %q",
+			opts.InstanceName,
+		)
+	}
+
+	instance, err := deployEngine.UpdateBlueprintInstance(ctx, instanceID, payload)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+	return instance, nil
+}
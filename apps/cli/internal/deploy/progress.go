@@ -0,0 +1,190 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// ElementKind is the kind of blueprint instance element
+// that progress is tracked for.
+type ElementKind string
+
+const (
+	// ElementKindResource is used for resources in a blueprint instance.
+	ElementKindResource ElementKind = "resource"
+	// ElementKindChild is used for child blueprints in a blueprint instance.
+	ElementKindChild ElementKind = "child"
+	// ElementKindLink is used for links between resources in a blueprint instance.
+	ElementKindLink ElementKind = "link"
+)
+
+// ElementProgress holds the latest known status of a resource,
+// child blueprint or link in a deployment or removal process.
+type ElementProgress struct {
+	Kind           ElementKind
+	Name           string
+	Status         string
+	Category       StatusCategory
+	FailureReasons []string
+	Started        time.Time
+	Updated        time.Time
+}
+
+// Elapsed returns the time that has passed since the first event
+// was received for the element.
+// For elements that are no longer in progress, this is the time
+// between the first and the last event received for the element.
+func (e *ElementProgress) Elapsed(now time.Time) time.Duration {
+	if e.Category == StatusCategoryInProgress {
+		return now.Sub(e.Started)
+	}
+
+	return e.Updated.Sub(e.Started)
+}
+
+// Progress tracks the progress of a deployment or removal
+// process from a stream of blueprint instance events.
+type Progress struct {
+	elements       []*ElementProgress
+	elementLookup  map[string]*ElementProgress
+	instanceStatus bpcore.InstanceStatus
+	finished       bool
+	failureReasons []string
+}
+
+// NewProgress creates a new tracker for the progress of a deployment
+// or removal process.
+func NewProgress() *Progress {
+	return &Progress{
+		elements:       []*ElementProgress{},
+		elementLookup:  map[string]*ElementProgress{},
+		instanceStatus: bpcore.InstanceStatusPreparing,
+	}
+}
+
+// Apply updates the tracked progress with the provided event.
+// This returns the progress of the element that the event was for
+// or nil if the event was for the blueprint instance as a whole.
+func (p *Progress) Apply(event *types.BlueprintInstanceEvent, receivedAt time.Time) *ElementProgress {
+	switch event.GetType() {
+	case types.BlueprintInstanceEventTypeResourceUpdate:
+		resourceUpdate, _ := event.AsResourceUpdate()
+		return p.updateElement(
+			ElementKindResource,
+			resourceUpdate.ResourceName,
+			ResourceStatusName(resourceUpdate.Status),
+			ResourceStatusCategory(resourceUpdate.Status),
+			resourceUpdate.FailureReasons,
+			receivedAt,
+		)
+	case types.BlueprintInstanceEventTypeChildUpdate:
+		childUpdate, _ := event.AsChildUpdate()
+		return p.updateElement(
+			ElementKindChild,
+			childUpdate.ChildName,
+			InstanceStatusName(childUpdate.Status),
+			InstanceStatusCategory(childUpdate.Status),
+			childUpdate.FailureReasons,
+			receivedAt,
+		)
+	case types.BlueprintInstanceEventTypeLinkUpdate:
+		linkUpdate, _ := event.AsLinkUpdate()
+		return p.updateElement(
+			ElementKindLink,
+			linkUpdate.LinkName,
+			LinkStatusName(linkUpdate.Status),
+			LinkStatusCategory(linkUpdate.Status),
+			linkUpdate.FailureReasons,
+			receivedAt,
+		)
+	case types.BlueprintInstanceEventTypeInstanceUpdate:
+		instanceUpdate, _ := event.AsInstanceUpdate()
+		p.instanceStatus = instanceUpdate.Status
+	case types.BlueprintInstanceEventTypeDeployFinished:
+		finish, _ := event.AsFinish()
+		p.instanceStatus = finish.Status
+		p.failureReasons = finish.FailureReasons
+		p.finished = true
+	}
+
+	return nil
+}
+
+func (p *Progress) updateElement(
+	kind ElementKind,
+	name string,
+	status string,
+	category StatusCategory,
+	failureReasons []string,
+	receivedAt time.Time,
+) *ElementProgress {
+	key := string(kind) + ":" + name
+	element, exists := p.elementLookup[key]
+	if !exists {
+		element = &ElementProgress{
+			Kind:    kind,
+			Name:    name,
+			Started: receivedAt,
+		}
+		p.elementLookup[key] = element
+		p.elements = append(p.elements, element)
+	}
+
+	element.Status = status
+	element.Category = category
+	element.FailureReasons = failureReasons
+	element.Updated = receivedAt
+	return element
+}
+
+// Elements returns the progress of each element in the order that
+// the first event for each element was received.
+func (p *Progress) Elements() []*ElementProgress {
+	return p.elements
+}
+
+// InstanceStatus returns the latest known status of the blueprint instance.
+func (p *Progress) InstanceStatus() bpcore.InstanceStatus {
+	return p.instanceStatus
+}
+
+// Finished determines whether the deploy engine has reported
+// that the deployment or removal process has finished.
+func (p *Progress) Finished() bool {
+	return p.finished
+}
+
+// FailureReasons returns the reasons reported by the deploy engine
+// for the failure of the deployment or removal process.
+func (p *Progress) FailureReasons() []string {
+	return p.failureReasons
+}
+
+// Err returns an error describing the failure of the deployment
+// or removal process when the deploy engine reported that
+// it finished unsuccessfully.
+// This returns nil if the process has not finished or was successful.
+func (p *Progress) Err() error {
+	if !p.finished {
+		return nil
+	}
+
+	category := InstanceStatusCategory(p.instanceStatus)
+	if category != StatusCategoryFailure && category != StatusCategoryRolledBack {
+		return nil
+	}
+
+	if len(p.failureReasons) == 0 {
+		return fmt.Errorf("finished with status %q", InstanceStatusName(p.instanceStatus))
+	}
+
+	return fmt.Errorf(
+		"finished with status %q:\n  - %s",
+		InstanceStatusName(p.instanceStatus),
+		strings.Join(p.failureReasons, "\n  - "),
+	)
+}
@@ -0,0 +1,220 @@
+package deploy
+
+import (
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+// StatusCategory is an enum that groups the statuses of resources,
+// links, child blueprints and blueprint instances for display purposes.
+type StatusCategory int
+
+const (
+	// StatusCategoryInProgress is used for statuses where
+	// a deployment or removal is still in progress.
+	StatusCategoryInProgress StatusCategory = iota
+	// StatusCategorySuccess is used for statuses where
+	// a deployment or removal has completed successfully.
+	StatusCategorySuccess
+	// StatusCategoryFailure is used for statuses where
+	// a deployment, removal or rollback has failed.
+	StatusCategoryFailure
+	// StatusCategoryRolledBack is used for statuses where
+	// a failed deployment or removal has been rolled back.
+	StatusCategoryRolledBack
+)
+
+// ResourceStatusName returns a human-readable name
+// for the provided resource status.
+func ResourceStatusName(status bpcore.ResourceStatus) string {
+	switch status {
+	case bpcore.ResourceStatusCreating:
+		return "creating"
+	case bpcore.ResourceStatusCreated:
+		return "created"
+	case bpcore.ResourceStatusCreateFailed:
+		return "create failed"
+	case bpcore.ResourceStatusDestroying:
+		return "destroying"
+	case bpcore.ResourceStatusDestroyed:
+		return "destroyed"
+	case bpcore.ResourceStatusDestroyFailed:
+		return "destroy failed"
+	case bpcore.ResourceStatusUpdating:
+		return "updating"
+	case bpcore.ResourceStatusUpdated:
+		return "updated"
+	case bpcore.ResourceStatusUpdateFailed:
+		return "update failed"
+	case bpcore.ResourceStatusRollingBack:
+		return "rolling back"
+	case bpcore.ResourceStatusRollbackFailed:
+		return "rollback failed"
+	case bpcore.ResourceStatusRollbackComplete:
+		return "rollback complete"
+	default:
+		return "unknown"
+	}
+}
+
+// ResourceStatusCategory determines the display category
+// for the provided resource status.
+func ResourceStatusCategory(status bpcore.ResourceStatus) StatusCategory {
+	switch status {
+	case bpcore.ResourceStatusCreated,
+		bpcore.ResourceStatusDestroyed,
+		bpcore.ResourceStatusUpdated:
+		return StatusCategorySuccess
+	case bpcore.ResourceStatusCreateFailed,
+		bpcore.ResourceStatusDestroyFailed,
+		bpcore.ResourceStatusUpdateFailed,
+		bpcore.ResourceStatusRollbackFailed:
+		return StatusCategoryFailure
+	case bpcore.ResourceStatusRollbackComplete:
+		return StatusCategoryRolledBack
+	default:
+		return StatusCategoryInProgress
+	}
+}
+
+// LinkStatusName returns a human-readable name
+// for the provided link status.
+func LinkStatusName(status bpcore.LinkStatus) string {
+	switch status {
+	case bpcore.LinkStatusCreating:
+		return "creating"
+	case bpcore.LinkStatusCreated:
+		return "created"
+	case bpcore.LinkStatusCreateFailed:
+		return "create failed"
+	case bpcore.LinkStatusCreateRollingBack:
+		return "create rolling back"
+	case bpcore.LinkStatusCreateRollbackFailed:
+		return "create rollback failed"
+	case bpcore.LinkStatusCreateRollbackComplete:
+		return "create rollback complete"
+	case bpcore.LinkStatusDestroying:
+		return "destroying"
+	case bpcore.LinkStatusDestroyed:
+		return "destroyed"
+	case bpcore.LinkStatusDestroyFailed:
+		return "destroy failed"
+	case bpcore.LinkStatusDestroyRollingBack:
+		return "destroy rolling back"
+	case bpcore.LinkStatusDestroyRollbackFailed:
+		return "destroy rollback failed"
+	case bpcore.LinkStatusDestroyRollbackComplete:
+		return "destroy rollback complete"
+	case bpcore.LinkStatusUpdating:
+		return "updating"
+	case bpcore.LinkStatusUpdated:
+		return "updated"
+	case bpcore.LinkStatusUpdateFailed:
+		return "update failed"
+	case bpcore.LinkStatusUpdateRollingBack:
+		return "update rolling back"
+	case bpcore.LinkStatusUpdateRollbackFailed:
+		return "update rollback failed"
+	case bpcore.LinkStatusUpdateRollbackComplete:
+		return "update rollback complete"
+	default:
+		return "unknown"
+	}
+}
+
+// LinkStatusCategory determines the display category
+// for the provided link status.
+func LinkStatusCategory(status bpcore.LinkStatus) StatusCategory {
+	switch status {
+	case bpcore.LinkStatusCreated,
+		bpcore.LinkStatusDestroyed,
+		bpcore.LinkStatusUpdated:
+		return StatusCategorySuccess
+	case bpcore.LinkStatusCreateFailed,
+		bpcore.LinkStatusCreateRollbackFailed,
+		bpcore.LinkStatusDestroyFailed,
+		bpcore.LinkStatusDestroyRollbackFailed,
+		bpcore.LinkStatusUpdateFailed,
+		bpcore.LinkStatusUpdateRollbackFailed:
+		return StatusCategoryFailure
+	case bpcore.LinkStatusCreateRollbackComplete,
+		bpcore.LinkStatusDestroyRollbackComplete,
+		bpcore.LinkStatusUpdateRollbackComplete:
+		return StatusCategoryRolledBack
+	default:
+		return StatusCategoryInProgress
+	}
+}
+
+// InstanceStatusName returns a human-readable name
+// for the provided blueprint instance status.
+// This is also used for child blueprints.
+func InstanceStatusName(status bpcore.InstanceStatus) string {
+	switch status {
+	case bpcore.InstanceStatusPreparing:
+		return "preparing"
+	case bpcore.InstanceStatusDeploying:
+		return "deploying"
+	case bpcore.InstanceStatusDeployed:
+		return "deployed"
+	case bpcore.InstanceStatusDeployFailed:
+		return "deploy failed"
+	case bpcore.InstanceStatusDeployRollingBack:
+		return "deploy rolling back"
+	case bpcore.InstanceStatusDeployRollbackFailed:
+		return "deploy rollback failed"
+	case bpcore.InstanceStatusDeployRollbackComplete:
+		return "deploy rollback complete"
+	case bpcore.InstanceStatusDestroying:
+		return "destroying"
+	case bpcore.InstanceStatusDestroyed:
+		return "destroyed"
+	case bpcore.InstanceStatusDestroyFailed:
+		return "destroy failed"
+	case bpcore.InstanceStatusDestroyRollingBack:
+		return "destroy rolling back"
+	case bpcore.InstanceStatusDestroyRollbackFailed:
+		return "destroy rollback failed"
+	case bpcore.InstanceStatusDestroyRollbackComplete:
+		return "destroy rollback complete"
+	case bpcore.InstanceStatusUpdating:
+		return "updating"
+	case bpcore.InstanceStatusUpdated:
+		return "updated"
+	case bpcore.InstanceStatusUpdateFailed:
+		return "update failed"
+	case bpcore.InstanceStatusUpdateRollingBack:
+		return "update rolling back"
+	case bpcore.InstanceStatusUpdateRollbackFailed:
+		return "update rollback failed"
+	case bpcore.InstanceStatusUpdateRollbackComplete:
+		return "update rollback complete"
+	case bpcore.InstanceStatusNotDeployed:
+		return "not deployed"
+	default:
+		return "unknown"
+	}
+}
+
+// InstanceStatusCategory determines the display category
+// for the provided blueprint instance status.
+func InstanceStatusCategory(status bpcore.InstanceStatus) StatusCategory {
+	switch status {
+	case bpcore.InstanceStatusDeployed,
+		bpcore.InstanceStatusDestroyed,
+		bpcore.InstanceStatusUpdated:
+		return StatusCategorySuccess
+	case bpcore.InstanceStatusDeployFailed,
+		bpcore.InstanceStatusDeployRollbackFailed,
+		bpcore.InstanceStatusDestroyFailed,
+		bpcore.InstanceStatusDestroyRollbackFailed,
+		bpcore.InstanceStatusUpdateFailed,
+		bpcore.InstanceStatusUpdateRollbackFailed:
+		return StatusCategoryFailure
+	case bpcore.InstanceStatusDeployRollbackComplete,
+		bpcore.InstanceStatusDestroyRollbackComplete,
+		bpcore.InstanceStatusUpdateRollbackComplete:
+		return StatusCategoryRolledBack
+	default:
+		return StatusCategoryInProgress
+	}
+}
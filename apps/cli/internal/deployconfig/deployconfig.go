@@ -0,0 +1,430 @@
+package deployconfig
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+)
+
+const (
+	// SectionProviders is the section of the deploy config file
+	// that holds configuration for provider plugins.
+	SectionProviders = "providers"
+	// SectionTransformers is the section of the deploy config file
+	// that holds configuration for transformer plugins.
+	SectionTransformers = "transformers"
+	// SectionContextVariables is the section of the deploy config file
+	// that holds context variables that are made available to plugins.
+	SectionContextVariables = "contextVariables"
+	// SectionBlueprintVariables is the section of the deploy config file
+	// that holds overrides for the variables defined in a blueprint.
+	SectionBlueprintVariables = "blueprintVariables"
+)
+
+var (
+	// Sections is a list of all the top-level sections
+	// supported in a deploy config file.
+	Sections = []string{
+		SectionProviders,
+		SectionTransformers,
+		SectionContextVariables,
+		SectionBlueprintVariables,
+	}
+
+	pluginNamespacePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+// Options holds the options for loading a deploy config file.
+type Options struct {
+	// Required determines whether a missing deploy config file is an error,
+	// this should only be false when the default deploy config file path is used.
+	Required bool
+	// Environment is the name of an environment to load the deploy config
+	// overlay file for, for example, "staging" for "celerity.deploy.staging.yaml".
+	// No overlay file is loaded when this is empty.
+	Environment string
+	// SkipPluginConfigValidation skips the checks for the plugin-specific
+	// entries in the "providers" and "transformers" sections.
+	SkipPluginConfigValidation bool
+	// Interpolator resolves references in string values,
+	// when nil, string values are used as they are.
+	Interpolator *config.Interpolator
+}
+
+// File is a deploy config file that has been loaded and checked.
+type File struct {
+	Path   string
+	Config *types.BlueprintOperationConfig
+}
+
+// Load loads the deploy config file at the provided path along with the
+// overlay file for the environment in the provided options,
+// see LoadFiles for how files are found and Merge for how they are merged.
+// The returned config is nil when there are no deploy config files
+// and they are not required.
+func Load(path string, opts *Options) (*types.BlueprintOperationConfig, error) {
+	files, err := LoadFiles(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return Merge(files...), nil
+}
+
+// LoadFiles reads, parses and checks the structure of the deploy config file
+// at the provided path followed by the overlay file for the environment in the
+// provided options.
+// YAML, JSON and TOML deploy config files are supported, the format of each file
+// is determined by the file extension.
+//
+// When the deploy config file is not required and does not exist, a file with
+// the same name and one of the other supported extensions will be used instead,
+// for example, "celerity.deploy.yaml" in place of "celerity.deploy.json".
+// The overlay file for an environment has the environment name added before the extension
+// of the deploy config file, for example, "celerity.deploy.staging.yaml",
+// the overlay file can use any of the supported extensions and must exist.
+func LoadFiles(path string, opts *Options) ([]*File, error) {
+	files := []*File{}
+	basePath, err := findFile(path, opts.Required)
+	if err != nil {
+		return nil, err
+	}
+
+	if basePath != "" {
+		file, err := loadFile(basePath, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if opts.Environment == "" {
+		return files, nil
+	}
+
+	overlayPath, err := findEnvironmentFile(path, opts.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := loadFile(overlayPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, overlay), nil
+}
+
+// WatchPaths returns the paths of all the deploy config files that could be
+// loaded by LoadFiles for the provided path and options, including files that
+// do not exist yet, so they can be watched for changes.
+func WatchPaths(path string, opts *Options) []string {
+	paths := []string{path}
+	if !opts.Required {
+		paths = append(paths, alternativePaths(trimExtension(path))...)
+	}
+
+	if opts.Environment != "" {
+		paths = append(paths, alternativePaths(trimExtension(path)+"."+opts.Environment)...)
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// Merge deep merges the provided deploy config files,
+// where values in later files take precedence over values in earlier files.
+// nil is returned when no files are provided.
+func Merge(files ...*File) *types.BlueprintOperationConfig {
+	if len(files) == 0 {
+		return nil
+	}
+
+	merged := &types.BlueprintOperationConfig{
+		Providers:          map[string]map[string]*core.ScalarValue{},
+		Transformers:       map[string]map[string]*core.ScalarValue{},
+		ContextVariables:   map[string]*core.ScalarValue{},
+		BlueprintVariables: map[string]*core.ScalarValue{},
+	}
+	for _, file := range files {
+		mergePluginConfig(merged.Providers, file.Config.Providers)
+		mergePluginConfig(merged.Transformers, file.Config.Transformers)
+		maps.Copy(merged.ContextVariables, file.Config.ContextVariables)
+		maps.Copy(merged.BlueprintVariables, file.Config.BlueprintVariables)
+	}
+
+	return merged
+}
+
+func mergePluginConfig(
+	dest map[string]map[string]*core.ScalarValue,
+	source map[string]map[string]*core.ScalarValue,
+) {
+	for namespace, pluginConfig := range source {
+		if _, exists := dest[namespace]; !exists {
+			dest[namespace] = map[string]*core.ScalarValue{}
+		}
+		maps.Copy(dest[namespace], pluginConfig)
+	}
+}
+
+func loadFile(path string, opts *Options) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deploy config file: %w", err)
+	}
+
+	format, err := config.FileFormatFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("deploy config file %q: %w", path, err)
+	}
+
+	deployConfig, err := Parse(data, format, path, opts)
+	if err != nil {
+		return nil, withFileContext("deploy config file", path, err)
+	}
+
+	return &File{
+		Path:   path,
+		Config: deployConfig,
+	}, nil
+}
+
+// findFile resolves the deploy config file to load,
+// an empty path is returned when the file is not required and
+// there is no deploy config file with any of the supported extensions.
+func findFile(path string, required bool) (string, error) {
+	exists, err := fileExists(path)
+	if err != nil || exists {
+		return path, err
+	}
+
+	if required {
+		return "", fmt.Errorf("deploy config file %q does not exist", path)
+	}
+
+	for _, candidate := range alternativePaths(trimExtension(path)) {
+		exists, err := fileExists(candidate)
+		if err != nil || exists {
+			return candidate, err
+		}
+	}
+
+	return "", nil
+}
+
+func findEnvironmentFile(path string, environment string) (string, error) {
+	candidates := alternativePaths(trimExtension(path) + "." + environment)
+	for _, candidate := range candidates {
+		exists, err := fileExists(candidate)
+		if err != nil || exists {
+			return candidate, err
+		}
+	}
+
+	return "", fmt.Errorf(
+		"no deploy config file found for environment %q, expected one of %s",
+		environment,
+		quoteAll(candidates),
+	)
+}
+
+func alternativePaths(pathWithoutExt string) []string {
+	paths := make([]string, 0, len(config.FileExtensions))
+	for _, ext := range config.FileExtensions {
+		paths = append(paths, pathWithoutExt+ext)
+	}
+	return paths
+}
+
+func trimExtension(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+func fileExists(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !info.IsDir(), nil
+}
+
+// withFileContext prefixes each of the errors reported for
+// a file with the kind and path of the file.
+func withFileContext(fileKind string, path string, err error) error {
+	joined, isJoined := err.(interface{ Unwrap() []error })
+	if !isJoined {
+		return fmt.Errorf("%s %q: %w", fileKind, path, err)
+	}
+
+	errs := []error{}
+	for _, itemErr := range joined.Unwrap() {
+		errs = append(errs, fmt.Errorf("%s %q: %w", fileKind, path, itemErr))
+	}
+	return errors.Join(errs...)
+}
+
+// Parse parses and checks the structure of the contents of a deploy config file
+// at the provided path in the provided format, relative paths in file references
+// are resolved from the directory of the deploy config file.
+// All structural errors are reported together so they can be fixed in one pass.
+func Parse(
+	data []byte,
+	format config.FileFormat,
+	path string,
+	opts *Options,
+) (*types.BlueprintOperationConfig, error) {
+	root, err := decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	rootMap, isMap := root.(map[string]any)
+	if !isMap {
+		return nil, fmt.Errorf("expected an object at the top level, found %s", describeValue(root))
+	}
+
+	builder := &configBuilder{
+		opts: opts,
+		path: path,
+	}
+	deployConfig := &types.BlueprintOperationConfig{
+		Providers:          builder.pluginSections(rootMap, SectionProviders),
+		Transformers:       builder.pluginSections(rootMap, SectionTransformers),
+		ContextVariables:   builder.scalarSection(rootMap[SectionContextVariables], SectionContextVariables),
+		BlueprintVariables: builder.scalarSection(rootMap[SectionBlueprintVariables], SectionBlueprintVariables),
+	}
+
+	for _, key := range sortedKeys(rootMap) {
+		if !slices.Contains(Sections, key) {
+			builder.addError(key, fmt.Errorf(
+				"unknown section, must be one of %s",
+				quoteAll(Sections),
+			))
+		}
+	}
+
+	if len(builder.errs) > 0 {
+		return nil, errors.Join(builder.errs...)
+	}
+
+	return deployConfig, nil
+}
+
+// configBuilder converts the sections of a decoded deploy config file
+// into the deploy engine payload format, collecting errors along the way.
+type configBuilder struct {
+	opts *Options
+	// path is the path of the file being converted.
+	path string
+	errs []error
+}
+
+func (b *configBuilder) pluginSections(
+	rootMap map[string]any,
+	sectionName string,
+) map[string]map[string]*core.ScalarValue {
+	section, isSection := b.section(rootMap[sectionName], sectionName)
+	if !isSection {
+		return map[string]map[string]*core.ScalarValue{}
+	}
+
+	plugins := make(map[string]map[string]*core.ScalarValue, len(section))
+	for _, namespace := range sortedKeys(section) {
+		path := joinPath(sectionName, namespace)
+		if !b.opts.SkipPluginConfigValidation && !pluginNamespacePattern.MatchString(namespace) {
+			b.addError(path, errors.New(
+				"invalid plugin namespace, must start with a letter or digit "+
+					"and only contain letters, digits, \"-\" and \"_\"",
+			))
+		}
+
+		plugins[namespace] = b.scalarSection(section[namespace], path)
+	}
+
+	return plugins
+}
+
+func (b *configBuilder) scalarSection(value any, path string) map[string]*core.ScalarValue {
+	section, isSection := b.section(value, path)
+	if !isSection {
+		return map[string]*core.ScalarValue{}
+	}
+
+	isPluginConfig := strings.HasPrefix(path, SectionProviders+".") ||
+		strings.HasPrefix(path, SectionTransformers+".")
+
+	values := make(map[string]*core.ScalarValue, len(section))
+	for _, key := range sortedKeys(section) {
+		itemPath := joinPath(path, key)
+		if isPluginConfig && !b.opts.SkipPluginConfigValidation &&
+			(strings.TrimSpace(key) == "" || strings.ContainsAny(key, " \t\r\n")) {
+			b.addError(itemPath, errors.New("plugin config keys must not be empty or contain whitespace"))
+			continue
+		}
+
+		scalar, err := b.scalar(section[key])
+		if err != nil {
+			b.addError(itemPath, err)
+			continue
+		}
+		values[key] = scalar
+	}
+
+	return values
+}
+
+func (b *configBuilder) section(value any, path string) (map[string]any, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	section, isSection := value.(map[string]any)
+	if !isSection {
+		b.addError(path, fmt.Errorf("expected an object, found %s", describeValue(value)))
+		return nil, false
+	}
+
+	return section, true
+}
+
+func (b *configBuilder) addError(path string, err error) {
+	b.errs = append(b.errs, fmt.Errorf("%s: %w", path, err))
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(section map[string]any) []string {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, ", ")
+}
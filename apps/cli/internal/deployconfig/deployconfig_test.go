@@ -0,0 +1,461 @@
+package deployconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	want := &types.BlueprintOperationConfig{
+		Providers: map[string]map[string]*core.ScalarValue{
+			"aws": {
+				"region":     core.ScalarFromString("eu-west-2"),
+				"maxRetries": core.ScalarFromInt(3),
+			},
+		},
+		Transformers: map[string]map[string]*core.ScalarValue{
+			"celerity": {
+				"timeoutFactor": core.ScalarFromFloat(1.5),
+			},
+		},
+		ContextVariables: map[string]*core.ScalarValue{
+			"debug": core.ScalarFromBool(true),
+		},
+		BlueprintVariables: map[string]*core.ScalarValue{
+			"environment": core.ScalarFromString("staging"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		format config.FileFormat
+		data   string
+	}{
+		{
+			name:   "yaml",
+			format: config.FileFormatYAML,
+			data: "providers:\n" +
+				"  aws:\n" +
+				"    region: eu-west-2\n" +
+				"    maxRetries: 3\n" +
+				"transformers:\n" +
+				"  celerity:\n" +
+				"    timeoutFactor: 1.5\n" +
+				"contextVariables:\n" +
+				"  debug: true\n" +
+				"blueprintVariables:\n" +
+				"  environment: staging\n",
+		},
+		{
+			name:   "toml",
+			format: config.FileFormatTOML,
+			data: "[providers.aws]\n" +
+				"region = \"eu-west-2\"\n" +
+				"maxRetries = 3\n" +
+				"\n" +
+				"[transformers.celerity]\n" +
+				"timeoutFactor = 1.5\n" +
+				"\n" +
+				"[contextVariables]\n" +
+				"debug = true\n" +
+				"\n" +
+				"[blueprintVariables]\n" +
+				"environment = \"staging\"\n",
+		},
+		{
+			name:   "json",
+			format: config.FileFormatJSON,
+			data: `{
+  "providers": {"aws": {"region": "eu-west-2", "maxRetries": 3}},
+  "transformers": {"celerity": {"timeoutFactor": 1.5}},
+  "contextVariables": {"debug": true},
+  "blueprintVariables": {"environment": "staging"}
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployConfig, err := Parse([]byte(test.data), test.format, "celerity.deploy.yaml", &Options{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(deployConfig, want) {
+				t.Errorf("unexpected deploy config\nwant: %s\ngot:  %s", describeConfig(want), describeConfig(deployConfig))
+			}
+		})
+	}
+}
+
+func TestParseEmptyYAML(t *testing.T) {
+	deployConfig, err := Parse([]byte("# No config yet\n"), config.FileFormatYAML, "celerity.deploy.yaml", &Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(deployConfig.Providers) != 0 || len(deployConfig.BlueprintVariables) != 0 {
+		t.Errorf("expected an empty deploy config, got %s", describeConfig(deployConfig))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  config.FileFormat
+		data    string
+		opts    *Options
+		wantErr []string
+	}{
+		{
+			name:   "json syntax error",
+			format: config.FileFormatJSON,
+			data: "{\n" +
+				"  \"providers\": {\n" +
+				"    \"aws\": {\"region\": \"eu-west-2\",}\n" +
+				"  }\n" +
+				"}\n",
+			wantErr: []string{"invalid JSON at line 3, column 35"},
+		},
+		{
+			name:    "json unexpected end of file",
+			format:  config.FileFormatJSON,
+			data:    "{\n  \"providers\": {}\n",
+			wantErr: []string{"invalid JSON at line 3, column 1: unexpected end of file"},
+		},
+		{
+			name:    "json content after the top level object",
+			format:  config.FileFormatJSON,
+			data:    "{}\n{}\n",
+			wantErr: []string{"unexpected content after the top level object at line 2, column 1"},
+		},
+		{
+			name:    "json empty file",
+			format:  config.FileFormatJSON,
+			data:    "\n",
+			wantErr: []string{"the file is empty"},
+		},
+		{
+			name:   "toml syntax error",
+			format: config.FileFormatTOML,
+			data: "[providers.aws]\n" +
+				"maxRetries = 3\n" +
+				"region = eu-west-2\n",
+			wantErr: []string{"invalid TOML at line 3, column 10"},
+		},
+		{
+			name:   "yaml syntax error",
+			format: config.FileFormatYAML,
+			data: "providers:\n" +
+				"  aws:\n" +
+				"\tregion: eu-west-2\n",
+			wantErr: []string{"invalid YAML: yaml: line 3"},
+		},
+		{
+			name:    "top level is not an object",
+			format:  config.FileFormatYAML,
+			data:    "- providers\n",
+			wantErr: []string{"expected an object at the top level, found an array"},
+		},
+		{
+			name:   "structural errors are reported together",
+			format: config.FileFormatYAML,
+			data: "providers:\n" +
+				"  aws:\n" +
+				"    regions: [eu-west-1, eu-west-2]\n" +
+				"    tags: {team: platform}\n" +
+				"  \"@invalid\":\n" +
+				"    region: eu-west-2\n" +
+				"contextVariables: debug\n" +
+				"variables:\n" +
+				"  environment: staging\n",
+			wantErr: []string{
+				"providers.aws.regions: expected a string, number or boolean, found an array",
+				"providers.aws.tags: expected a string, number or boolean, found an object",
+				"providers.@invalid: invalid plugin namespace",
+				"contextVariables: expected an object, found a string",
+				"variables: unknown section",
+			},
+		},
+		{
+			name:   "plugin config keys with whitespace",
+			format: config.FileFormatJSON,
+			data:   `{"transformers": {"celerity": {"invalid key": "value"}}}`,
+			wantErr: []string{
+				"transformers.celerity.invalid key: plugin config keys must not be empty or contain whitespace",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := test.opts
+			if opts == nil {
+				opts = &Options{}
+			}
+
+			_, err := Parse([]byte(test.data), test.format, "celerity.deploy.yaml", opts)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range test.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected the error to contain %q, got:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSkipPluginConfigValidation(t *testing.T) {
+	data := `{"providers": {"@custom": {"invalid key": "value"}}}`
+	deployConfig, err := Parse([]byte(data), config.FileFormatJSON, "celerity.deploy.json", &Options{
+		SkipPluginConfigValidation: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deployConfig.Providers["@custom"]["invalid key"].ToString() != "value" {
+		t.Errorf("expected the plugin config to be kept, got %s", describeConfig(deployConfig))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := &File{
+		Path: "celerity.deploy.yaml",
+		Config: &types.BlueprintOperationConfig{
+			Providers: map[string]map[string]*core.ScalarValue{
+				"aws": {
+					"region":     core.ScalarFromString("eu-west-1"),
+					"maxRetries": core.ScalarFromInt(3),
+				},
+				"gcp": {
+					"project": core.ScalarFromString("base-project"),
+				},
+			},
+			ContextVariables: map[string]*core.ScalarValue{
+				"debug":    core.ScalarFromBool(false),
+				"logLevel": core.ScalarFromString("info"),
+			},
+			BlueprintVariables: map[string]*core.ScalarValue{
+				"environment": core.ScalarFromString("dev"),
+			},
+		},
+	}
+	overlay := &File{
+		Path: "celerity.deploy.staging.yaml",
+		Config: &types.BlueprintOperationConfig{
+			Providers: map[string]map[string]*core.ScalarValue{
+				"aws": {
+					"region": core.ScalarFromString("eu-west-2"),
+				},
+			},
+			Transformers: map[string]map[string]*core.ScalarValue{
+				"celerity": {
+					"timeoutFactor": core.ScalarFromFloat(1.5),
+				},
+			},
+			ContextVariables: map[string]*core.ScalarValue{
+				"debug": core.ScalarFromBool(true),
+			},
+			BlueprintVariables: map[string]*core.ScalarValue{
+				"environment": core.ScalarFromString("staging"),
+				"replicas":    core.ScalarFromInt(2),
+			},
+		},
+	}
+
+	want := &types.BlueprintOperationConfig{
+		Providers: map[string]map[string]*core.ScalarValue{
+			// Plugin config is merged for each key in a namespace.
+			"aws": {
+				"region":     core.ScalarFromString("eu-west-2"),
+				"maxRetries": core.ScalarFromInt(3),
+			},
+			"gcp": {
+				"project": core.ScalarFromString("base-project"),
+			},
+		},
+		Transformers: map[string]map[string]*core.ScalarValue{
+			"celerity": {
+				"timeoutFactor": core.ScalarFromFloat(1.5),
+			},
+		},
+		ContextVariables: map[string]*core.ScalarValue{
+			"debug":    core.ScalarFromBool(true),
+			"logLevel": core.ScalarFromString("info"),
+		},
+		BlueprintVariables: map[string]*core.ScalarValue{
+			"environment": core.ScalarFromString("staging"),
+			"replicas":    core.ScalarFromInt(2),
+		},
+	}
+
+	merged := Merge(base, overlay)
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("unexpected merged deploy config\nwant: %s\ngot:  %s", describeConfig(want), describeConfig(merged))
+	}
+
+	// The files that are merged must not be modified.
+	if base.Config.Providers["aws"]["region"].ToString() != "eu-west-1" {
+		t.Error("expected the base deploy config to be unchanged")
+	}
+}
+
+func TestMergeNoFiles(t *testing.T) {
+	if merged := Merge(); merged != nil {
+		t.Errorf("expected nil when there are no files, got %s", describeConfig(merged))
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "celerity.deploy.yaml",
+		"providers:\n"+
+			"  aws:\n"+
+			"    region: eu-west-1\n"+
+			"    maxRetries: 3\n",
+	)
+	writeTestFile(t, dir, "celerity.deploy.staging.toml",
+		"[providers.aws]\n"+
+			"region = \"eu-west-2\"\n",
+	)
+
+	// The overlay file can use a different format to the base file.
+	files, err := LoadFiles(filepath.Join(dir, "celerity.deploy.yaml"), &Options{
+		Required:    true,
+		Environment: "staging",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected the base and overlay files to be loaded, got %d files", len(files))
+	}
+	if filepath.Base(files[1].Path) != "celerity.deploy.staging.toml" {
+		t.Errorf("expected the overlay file to be loaded last, got %q", files[1].Path)
+	}
+
+	aws := Merge(files...).Providers["aws"]
+	if aws["region"].ToString() != "eu-west-2" || aws["maxRetries"].ToString() != "3" {
+		t.Errorf("expected the overlay file to take precedence over the base file, got %s", describeConfig(Merge(files...)))
+	}
+}
+
+func TestLoadFilesAlternativeExtension(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "celerity.deploy.json", `{"contextVariables": {"debug": true}}`)
+
+	files, err := LoadFiles(filepath.Join(dir, "celerity.deploy.yaml"), &Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(files) != 1 || filepath.Base(files[0].Path) != "celerity.deploy.json" {
+		t.Fatalf("expected the JSON deploy config file to be loaded, got %v", files)
+	}
+}
+
+func TestLoadFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "celerity.deploy.yaml", "providers: {}\n")
+	writeTestFile(t, dir, "invalid.deploy.json", "{\n  \"providers\": [\n")
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    *Options
+		wantErr string
+	}{
+		{
+			name:    "missing required file",
+			path:    filepath.Join(dir, "missing.deploy.yaml"),
+			opts:    &Options{Required: true},
+			wantErr: "missing.deploy.yaml\" does not exist",
+		},
+		{
+			name:    "missing overlay file",
+			path:    filepath.Join(dir, "celerity.deploy.yaml"),
+			opts:    &Options{Environment: "production"},
+			wantErr: "no deploy config file found for environment \"production\"",
+		},
+		{
+			name:    "errors include the file path",
+			path:    filepath.Join(dir, "invalid.deploy.json"),
+			opts:    &Options{Required: true},
+			wantErr: "invalid.deploy.json\": invalid JSON at line 3, column 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadFiles(test.path, test.opts)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadFilesNotRequired(t *testing.T) {
+	files, err := LoadFiles(filepath.Join(t.TempDir(), "celerity.deploy.yaml"), &Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(files) != 0 {
+		t.Errorf("expected no deploy config files, got %d", len(files))
+	}
+}
+
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func describeConfig(deployConfig *types.BlueprintOperationConfig) string {
+	if deployConfig == nil {
+		return "<nil>"
+	}
+
+	sb := strings.Builder{}
+	describePlugins(&sb, SectionProviders, deployConfig.Providers)
+	describePlugins(&sb, SectionTransformers, deployConfig.Transformers)
+	describeScalars(&sb, SectionContextVariables, deployConfig.ContextVariables)
+	describeScalars(&sb, SectionBlueprintVariables, deployConfig.BlueprintVariables)
+	return sb.String()
+}
+
+func describePlugins(sb *strings.Builder, section string, plugins map[string]map[string]*core.ScalarValue) {
+	for _, namespace := range sortedKeys(anyMap(plugins)) {
+		describeScalars(sb, joinPath(section, namespace), plugins[namespace])
+	}
+}
+
+func describeScalars(sb *strings.Builder, path string, values map[string]*core.ScalarValue) {
+	for _, key := range sortedKeys(anyMap(values)) {
+		sb.WriteString(joinPath(path, key) + "=" + values[key].ToString() + " ")
+	}
+}
+
+func anyMap[Value any](values map[string]Value) map[string]any {
+	converted := make(map[string]any, len(values))
+	for key, value := range values {
+		converted[key] = value
+	}
+	return converted
+}
@@ -0,0 +1,172 @@
+package deployconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// decode decodes the contents of a deploy config file in the provided format.
+func decode(data []byte, format config.FileFormat) (any, error) {
+	switch format {
+	case config.FileFormatYAML:
+		var root any
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		// An empty YAML document is treated as an empty deploy config.
+		if root == nil {
+			return map[string]any{}, nil
+		}
+		return root, nil
+	case config.FileFormatTOML:
+		root := map[string]any{}
+		if err := toml.Unmarshal(data, &root); err != nil {
+			return nil, withTOMLPosition(data, err)
+		}
+		return root, nil
+	}
+
+	return decodeJSON(data)
+}
+
+// decodeJSON decodes the contents of a deploy config file,
+// syntax errors are reported with the line and column where they occurred.
+func decodeJSON(data []byte) (any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("expected a JSON object, the file is empty")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept in their original form so integers
+	// can be distinguished from floating point values.
+	decoder.UseNumber()
+
+	var root any
+	if err := decoder.Decode(&root); err != nil {
+		return nil, withPosition(data, err)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		line, column := position(data, decoder.InputOffset())
+		return nil, fmt.Errorf(
+			"unexpected content after the top level object at line %d, column %d",
+			line,
+			column,
+		)
+	}
+
+	return root, nil
+}
+
+func withPosition(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := position(data, syntaxErr.Offset)
+		return fmt.Errorf("invalid JSON at line %d, column %d: %w", line, column, err)
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		line, column := position(data, int64(len(data)))
+		return fmt.Errorf("invalid JSON at line %d, column %d: unexpected end of file", line, column)
+	}
+
+	return fmt.Errorf("invalid JSON: %w", err)
+}
+
+// withTOMLPosition reports TOML syntax errors with the line and column
+// where they occurred in the same way as JSON syntax errors.
+func withTOMLPosition(data []byte, err error) error {
+	var parseErr toml.ParseError
+	if !errors.As(err, &parseErr) {
+		return fmt.Errorf("invalid TOML: %w", err)
+	}
+
+	// The message is only exposed without the position prefix
+	// as a part of the detailed error.
+	message, _, _ := strings.Cut(parseErr.ErrorWithPosition(), "\n\nAt line")
+	message = strings.TrimPrefix(message, "toml: error: ")
+	line, column := position(data, int64(parseErr.Position.Start)+1)
+	return fmt.Errorf("invalid TOML at line %d, column %d: %s", line, column, message)
+}
+
+// position converts a byte offset in the provided data
+// to a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	column := utf8.RuneCount(before[lineStart:])
+	// The offset of a syntax error is after the character that
+	// could not be parsed, an offset at the start of a line refers
+	// to the first column.
+	return line, max(column, 1)
+}
+
+func (b *configBuilder) scalar(value any) (*core.ScalarValue, error) {
+	switch typedValue := value.(type) {
+	case string:
+		if b.opts.Interpolator == nil {
+			return core.ScalarFromString(typedValue), nil
+		}
+
+		interpolated, err := b.opts.Interpolator.InterpolateString(typedValue, b.path)
+		if err != nil {
+			return nil, err
+		}
+		return core.ScalarFromString(interpolated), nil
+	case bool:
+		return core.ScalarFromBool(typedValue), nil
+	case int:
+		return core.ScalarFromInt(typedValue), nil
+	case int64:
+		return core.ScalarFromInt(int(typedValue)), nil
+	case uint64:
+		return core.ScalarFromInt(int(typedValue)), nil
+	case float64:
+		return core.ScalarFromFloat(typedValue), nil
+	case json.Number:
+		if intValue, err := typedValue.Int64(); err == nil {
+			return core.ScalarFromInt(int(intValue)), nil
+		}
+
+		floatValue, err := typedValue.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", typedValue)
+		}
+		return core.ScalarFromFloat(floatValue), nil
+	}
+
+	return nil, fmt.Errorf("expected a string, number or boolean, found %s", describeValue(value))
+}
+
+func describeValue(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number, int, int64, uint64, float64:
+		return "a number"
+	case map[any]any:
+		return "an object with keys that are not strings"
+	}
+
+	return fmt.Sprintf("a value of type %T", value)
+}
@@ -0,0 +1,149 @@
+package deployconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+)
+
+// VariableLayer is a set of blueprint variable overrides
+// that is merged over the blueprint variables in the deploy config file.
+type VariableLayer struct {
+	// Source describes where the variables were provided,
+	// for example, a var file path or the --var flag.
+	Source    string
+	Variables map[string]*core.ScalarValue
+}
+
+// LoadVarFile loads blueprint variable overrides from a YAML, JSON or TOML var file
+// that contains an object that maps variable names to scalar values.
+// String values can contain the same references as the deploy config file.
+func LoadVarFile(path string, interpolator *config.Interpolator) (*VariableLayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read var file: %w", err)
+	}
+
+	format, err := config.FileFormatFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("var file %q: %w", path, err)
+	}
+
+	root, err := decode(data, format)
+	if err != nil {
+		return nil, withFileContext("var file", path, err)
+	}
+
+	if _, isMap := root.(map[string]any); !isMap {
+		return nil, fmt.Errorf(
+			"var file %q: expected an object of variable names to values, found %s",
+			path,
+			describeValue(root),
+		)
+	}
+
+	builder := &configBuilder{
+		opts: &Options{Interpolator: interpolator},
+		path: path,
+	}
+	variables := builder.scalarSection(root, "")
+	if len(builder.errs) > 0 {
+		return nil, withFileContext("var file", path, errors.Join(builder.errs...))
+	}
+
+	return &VariableLayer{
+		Source:    fmt.Sprintf("var file %q", path),
+		Variables: variables,
+	}, nil
+}
+
+// ParseVars parses blueprint variable overrides provided as
+// "name=value" pairs such as those from the --var flag.
+// Values are parsed as JSON scalars so "10" is an integer, "1.5" is a float
+// and "true" is a boolean, a value can be quoted to force it to be
+// a string (e.g. '"10"'), any other value is used as a string as it is.
+func ParseVars(source string, vars map[string]string) (*VariableLayer, error) {
+	variables := make(map[string]*core.ScalarValue, len(vars))
+	for name, value := range vars {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s: variable names can not be empty, expected \"name=value\"", source)
+		}
+		variables[name] = ParseVarValue(value)
+	}
+
+	return &VariableLayer{
+		Source:    source,
+		Variables: variables,
+	}, nil
+}
+
+// ParseVarValue parses a single blueprint variable override value,
+// see ParseVars for how values are parsed.
+func ParseVarValue(value string) *core.ScalarValue {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+
+	var parsed any
+	if err := decoder.Decode(&parsed); err != nil || decoder.More() {
+		return core.ScalarFromString(value)
+	}
+
+	// Values are not interpolated as they are expected to be
+	// provided by the shell or CI environment that runs the CLI.
+	scalar, err := (&configBuilder{opts: &Options{}}).scalar(parsed)
+	if err != nil {
+		return core.ScalarFromString(value)
+	}
+
+	return scalar
+}
+
+// FileVariables returns the blueprint variables in a deploy config file
+// as a variable layer so the source of each blueprint variable can be tracked
+// when they are merged with MergeVariables.
+func FileVariables(file *File) *VariableLayer {
+	return &VariableLayer{
+		Source:    fmt.Sprintf("deploy config file %q", file.Path),
+		Variables: file.Config.BlueprintVariables,
+	}
+}
+
+// MergeVariables merges layers of blueprint variable overrides over the
+// blueprint variables in the provided deploy config, where later layers
+// take precedence over earlier layers.
+// The returned map holds the source of the value of each blueprint variable
+// that is provided in the layers.
+// A deploy config is created when the provided deploy config is nil
+// and there are variable overrides.
+func MergeVariables(
+	deployConfig *types.BlueprintOperationConfig,
+	layers ...*VariableLayer,
+) (*types.BlueprintOperationConfig, map[string]string) {
+	sources := map[string]string{}
+	for _, layer := range layers {
+		if len(layer.Variables) == 0 {
+			continue
+		}
+
+		if deployConfig == nil {
+			deployConfig = &types.BlueprintOperationConfig{}
+		}
+		if deployConfig.BlueprintVariables == nil {
+			deployConfig.BlueprintVariables = map[string]*core.ScalarValue{}
+		}
+
+		for name, value := range layer.Variables {
+			deployConfig.BlueprintVariables[name] = value
+			sources[name] = layer.Source
+		}
+	}
+
+	return deployConfig, sources
+}
@@ -0,0 +1,182 @@
+package deployconfig
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+func TestParseVarValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *core.ScalarValue
+	}{
+		{value: "10", want: core.ScalarFromInt(10)},
+		{value: "1.5", want: core.ScalarFromFloat(1.5)},
+		{value: "true", want: core.ScalarFromBool(true)},
+		{value: `"10"`, want: core.ScalarFromString("10")},
+		{value: "eu-west-2", want: core.ScalarFromString("eu-west-2")},
+		{value: "10 20", want: core.ScalarFromString("10 20")},
+		{value: `["a"]`, want: core.ScalarFromString(`["a"]`)},
+		{value: "", want: core.ScalarFromString("")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got := ParseVarValue(test.value)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %s, got %s", test.want.ToString(), got.ToString())
+			}
+		})
+	}
+}
+
+func TestParseVarsEmptyName(t *testing.T) {
+	_, err := ParseVars("--var", map[string]string{" ": "value"})
+	if err == nil || !strings.Contains(err.Error(), "variable names can not be empty") {
+		t.Fatalf("expected an error for an empty variable name, got %v", err)
+	}
+}
+
+func TestLoadVarFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		wantErr  string
+	}{
+		{
+			name:     "syntax error",
+			fileName: "vars.toml",
+			content:  "environment = \"staging\"\nreplicas = = 2\n",
+			wantErr:  "vars.toml\": invalid TOML at line 2, column 12",
+		},
+		{
+			name:     "not an object",
+			fileName: "list.yaml",
+			content:  "- environment\n",
+			wantErr:  "expected an object of variable names to values, found an array",
+		},
+		{
+			name:     "value that is not a scalar",
+			fileName: "nested.json",
+			content:  `{"environment": {"name": "staging"}}`,
+			wantErr:  "nested.json\": environment: expected a string, number or boolean, found an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeTestFile(t, dir, test.fileName, test.content)
+			_, err := LoadVarFile(path, nil)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMergeVariablesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	basePath := writeTestFile(t, dir, "celerity.deploy.yaml",
+		"blueprintVariables:\n"+
+			"  environment: dev\n"+
+			"  region: eu-west-1\n"+
+			"  replicas: 1\n"+
+			"  debug: true\n",
+	)
+	overlayPath := writeTestFile(t, dir, "celerity.deploy.staging.json",
+		`{"blueprintVariables": {"environment": "staging", "region": "eu-west-2", "replicas": 2}}`,
+	)
+	firstVarFilePath := writeTestFile(t, dir, "first.vars.toml",
+		"region = \"us-east-1\"\nreplicas = 3\n",
+	)
+	secondVarFilePath := writeTestFile(t, dir, "second.vars.yaml", "replicas: 4\n")
+
+	files, err := LoadFiles(basePath, &Options{Required: true, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Layers are provided in the same order as the commands that load
+	// the deploy config, deploy config files, followed by var files
+	// in the order they were provided and then the --var flag.
+	layers := []*VariableLayer{}
+	for _, file := range files {
+		layers = append(layers, FileVariables(file))
+	}
+	for _, varFilePath := range []string{firstVarFilePath, secondVarFilePath} {
+		layer, err := LoadVarFile(varFilePath, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		layers = append(layers, layer)
+	}
+	varsLayer, err := ParseVars("vars from --var", map[string]string{"replicas": "5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	layers = append(layers, varsLayer)
+
+	deployConfig, sources := MergeVariables(Merge(files...), layers...)
+
+	want := map[string]*core.ScalarValue{
+		"debug":       core.ScalarFromBool(true),
+		"environment": core.ScalarFromString("staging"),
+		"region":      core.ScalarFromString("us-east-1"),
+		"replicas":    core.ScalarFromInt(5),
+	}
+	if !reflect.DeepEqual(deployConfig.BlueprintVariables, want) {
+		t.Errorf("unexpected blueprint variables: %s", describeConfig(deployConfig))
+	}
+
+	wantSources := map[string]string{
+		"debug":       "deploy config file \"" + basePath + "\"",
+		"environment": "deploy config file \"" + overlayPath + "\"",
+		"region":      "var file \"" + firstVarFilePath + "\"",
+		"replicas":    "vars from --var",
+	}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Errorf("unexpected variable sources\nwant: %v\ngot:  %v", wantSources, sources)
+	}
+}
+
+func TestMergeVariablesWithoutDeployConfig(t *testing.T) {
+	layer, err := ParseVars("vars from --var", map[string]string{"environment": "staging"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deployConfig, _ := MergeVariables(nil, layer)
+	if deployConfig == nil || deployConfig.BlueprintVariables["environment"].ToString() != "staging" {
+		t.Fatalf("expected a deploy config to be created for the variable overrides, got %s", describeConfig(deployConfig))
+	}
+
+	emptyLayer, _ := ParseVars("vars from --var", map[string]string{})
+	if deployConfig, _ := MergeVariables(nil, emptyLayer); deployConfig != nil {
+		t.Errorf("expected no deploy config without variable overrides, got %s", describeConfig(deployConfig))
+	}
+}
+
+func TestWatchPaths(t *testing.T) {
+	dir := t.TempDir()
+	paths := WatchPaths(filepath.Join(dir, "celerity.deploy.yaml"), &Options{Environment: "staging"})
+
+	for _, name := range []string{
+		"celerity.deploy.yaml",
+		"celerity.deploy.json",
+		"celerity.deploy.staging.toml",
+	} {
+		found := false
+		for _, path := range paths {
+			found = found || path == filepath.Join(dir, name)
+		}
+		if !found {
+			t.Errorf("expected %q to be watched, got %v", name, paths)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package destroy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"go.uber.org/zap"
+)
+
+// Options holds the user-provided options for destroying
+// a blueprint instance.
+type Options struct {
+	// InstanceID is the ID of the blueprint instance to destroy.
+	InstanceID string
+	// InstanceName is the user-defined name of the blueprint
+	// instance to destroy.
+	InstanceName string
+	// Force allows protected blueprint instances to be destroyed
+	// once the user has confirmed the instance name.
+	Force bool
+	// ProtectedInstances is the list of blueprint instance names
+	// that are protected from being destroyed by accident.
+	ProtectedInstances []string
+	// DeployConfig is the config loaded from the deploy config file
+	// that is sent to the deploy engine, this is nil when there is
+	// no deploy config file.
+	DeployConfig *types.BlueprintOperationConfig
+}
+
+// Target holds information about the blueprint instance
+// that is going to be destroyed.
+type Target struct {
+	// InstanceName is the user-defined name of the blueprint instance.
+	InstanceName string
+	// Protected determines whether the blueprint instance is in the list
+	// of protected instances and requires confirmation to be destroyed.
+	Protected bool
+}
+
+// Prepare resolves the blueprint instance that is going to be destroyed
+// and checks whether it is protected from deletion.
+// This will return an error if the instance is protected and the force option
+// has not been set.
+func Prepare(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	logger *zap.Logger,
+) (*Target, error) {
+	if opts.InstanceID == "" && opts.InstanceName == "" {
+		return nil, ErrMissingInstance
+	}
+
+	instanceName := opts.InstanceName
+	if opts.InstanceID != "" {
+		instance, err := deployEngine.GetBlueprintInstance(ctx, opts.InstanceID)
+		if err != nil {
+			return nil, engine.SimplifyError(err, logger)
+		}
+		instanceName = instance.InstanceName
+	}
+
+	target := &Target{
+		InstanceName: instanceName,
+		Protected:    slices.Contains(opts.ProtectedInstances, instanceName),
+	}
+
+	if target.Protected && !opts.Force {
+		return nil, fmt.Errorf(
+			"blueprint instance %q is protected from deletion, "+
+				"use --force and confirm the instance name to destroy it",
+			instanceName,
+		)
+	}
+
+	return target, nil
+}
+
+// Confirm checks the instance name entered by the user
+// to confirm that a protected blueprint instance should be destroyed.
+func Confirm(target *Target, entered string) error {
+	if strings.TrimSpace(entered) != target.InstanceName {
+		return fmt.Errorf(
+			"the entered name does not match %q, the blueprint instance will not be destroyed",
+			target.InstanceName,
+		)
+	}
+
+	return nil
+}
+
+// StageChanges stages the changes for destroying the blueprint instance.
+func StageChanges(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	logger *zap.Logger,
+) (*stage.Result, error) {
+	return stage.Run(
+		ctx,
+		deployEngine,
+		&stage.Options{
+			InstanceID:   opts.InstanceID,
+			InstanceName: opts.InstanceName,
+			Destroy:      true,
+			DeployConfig: opts.DeployConfig,
+		},
+		logger,
+	)
+}
+
+// Start starts the destroy process for the blueprint instance
+// with the provided change set.
+func Start(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	staged *stage.Result,
+	logger *zap.Logger,
+) (*state.InstanceState, error) {
+	instanceID := opts.InstanceID
+	if instanceID == "" {
+		// The deploy engine resolves the instance name to an ID
+		// when staging changes for an existing instance.
+		instanceID = staged.InstanceID
+	}
+
+	if instanceID == "" {
+		return nil, fmt.Errorf(
+			"could not resolve an instance ID for the blueprint instance %q",
+			opts.InstanceName,
+		)
+	}
+
+	instance, err := deployEngine.DestroyBlueprintInstance(
+		ctx,
+		instanceID,
+		&types.DestroyBlueprintInstancePayload{
+			ChangeSetID: staged.ChangesetID,
+			Config:      opts.DeployConfig,
+		},
+	)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	if instance.InstanceID == "" {
+		instance.InstanceID = instanceID
+	}
+
+	return instance, nil
+}
+
+var (
+	// ErrMissingInstance is returned when neither an instance ID
+	// nor an instance name has been provided to destroy.
+	ErrMissingInstance = errors.New(
+		"an instance ID or name must be provided to destroy a blueprint instance",
+	)
+)
@@ -1,17 +1,184 @@
 package engine
 
 import (
-	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/common/sigv1"
 	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
 	"go.uber.org/zap"
 )
 
+const (
+	// AuthMethodNone is the config value for connecting to a deploy engine
+	// that does not require authentication, such as a deploy engine
+	// running on the same machine as the CLI.
+	AuthMethodNone = "none"
+	// AuthMethodAPIKey is the config value for authenticating
+	// with the deploy engine using an API key.
+	AuthMethodAPIKey = "api-key"
+	// AuthMethodOAuth2 is the config value for authenticating
+	// with the deploy engine using an access token obtained from an
+	// OAuth2 or OIDC provider through the client credentials grant type.
+	AuthMethodOAuth2 = "oauth2"
+	// AuthMethodCeleritySignatureV1 is the config value for authenticating
+	// with the deploy engine using the Celerity Signature v1 method.
+	AuthMethodCeleritySignatureV1 = "celerity-signature-v1"
+)
+
 // Create a new deploy engine client based on how the CLI is configured.
 func Create(confProvider *config.Provider, logger *zap.Logger) (DeployEngine, error) {
-	return deployengine.NewClient(
-		deployengine.WithClientAuthMethod(deployengine.AuthMethodAPIKey),
-		deployengine.WithClientEndpoint("http://localhost:8325"),
-		deployengine.WithClientConnectProtocol(deployengine.ConnectProtocolTCP),
-		deployengine.WithClientAPIKey("test-api-key"),
+	connectOpts, err := connectOptions(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	authOpts, err := authOptions(confProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployengine.NewClient(append(connectOpts, authOpts...)...)
+}
+
+func connectOptions(confProvider *config.Provider) ([]deployengine.ClientOption, error) {
+	connectProtocol, _ := confProvider.GetString("connectProtocol")
+	switch connectProtocol {
+	case "unix":
+		unixSocket, _ := confProvider.GetString("engine.unixSocket")
+		if unixSocket == "" {
+			unixSocket = deployengine.DefaultUnixDomainSocket
+		}
+		return []deployengine.ClientOption{
+			deployengine.WithClientConnectProtocol(deployengine.ConnectProtocolUnixDomainSocket),
+			deployengine.WithClientUnixDomainSocket(unixSocket),
+		}, nil
+	case "tcp":
+		endpoint, _ := confProvider.GetString("engine.endpoint")
+		if endpoint == "" {
+			endpoint = deployengine.DefaultEndpoint
+		}
+		return []deployengine.ClientOption{
+			deployengine.WithClientConnectProtocol(deployengine.ConnectProtocolTCP),
+			deployengine.WithClientEndpoint(endpoint),
+		}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"invalid connect protocol %q provided, must be either \"unix\" or \"tcp\"",
+		connectProtocol,
+	)
+}
+
+// authOptions reads the credentials for the configured auth method,
+// errors for config values that could not be resolved are returned before
+// checking for missing credentials as a reference that could not be resolved
+// would otherwise be reported as a missing config value.
+func authOptions(confProvider *config.Provider) ([]deployengine.ClientOption, error) {
+	authMethod, _ := confProvider.GetString("engine.authMethod")
+	switch authMethod {
+	case AuthMethodNone:
+		// The deploy engine client always sends credentials for one of
+		// its auth methods, an empty API key is sent when authentication
+		// is not required.
+		return []deployengine.ClientOption{
+			deployengine.WithClientAuthMethod(deployengine.AuthMethodAPIKey),
+			deployengine.WithClientAPIKey(""),
+		}, nil
+	case AuthMethodAPIKey:
+		apiKey, _ := confProvider.GetString("engine.auth.apiKey")
+		if err := confProvider.ValueErrors(); err != nil {
+			return nil, err
+		}
+		if apiKey == "" {
+			return nil, errMissingAuthConfig(authMethod, "engine.auth.apiKey")
+		}
+		return []deployengine.ClientOption{
+			deployengine.WithClientAuthMethod(deployengine.AuthMethodAPIKey),
+			deployengine.WithClientAPIKey(apiKey),
+		}, nil
+	case AuthMethodOAuth2:
+		return oauth2Options(confProvider)
+	case AuthMethodCeleritySignatureV1:
+		return signatureV1Options(confProvider)
+	}
+
+	return nil, fmt.Errorf(
+		"invalid engine auth method %q provided, must be one of %q, %q, %q or %q",
+		authMethod,
+		AuthMethodNone,
+		AuthMethodAPIKey,
+		AuthMethodOAuth2,
+		AuthMethodCeleritySignatureV1,
+	)
+}
+
+func oauth2Options(confProvider *config.Provider) ([]deployengine.ClientOption, error) {
+	providerBaseURL, _ := confProvider.GetString("engine.auth.oauth2.providerBaseURL")
+	tokenEndpoint, _ := confProvider.GetString("engine.auth.oauth2.tokenEndpoint")
+	clientID, _ := confProvider.GetString("engine.auth.oauth2.clientID")
+	clientSecret, _ := confProvider.GetString("engine.auth.oauth2.clientSecret")
+	if err := confProvider.ValueErrors(); err != nil {
+		return nil, err
+	}
+
+	if providerBaseURL == "" && tokenEndpoint == "" {
+		return nil, fmt.Errorf(
+			"the %q engine auth method requires either the \"engine.auth.oauth2.providerBaseURL\" "+
+				"or \"engine.auth.oauth2.tokenEndpoint\" config value to be set",
+			AuthMethodOAuth2,
+		)
+	}
+
+	if clientID == "" {
+		return nil, errMissingAuthConfig(AuthMethodOAuth2, "engine.auth.oauth2.clientID")
+	}
+
+	if clientSecret == "" {
+		return nil, errMissingAuthConfig(AuthMethodOAuth2, "engine.auth.oauth2.clientSecret")
+	}
+
+	return []deployengine.ClientOption{
+		deployengine.WithClientAuthMethod(deployengine.AuthMethodOAuth2),
+		deployengine.WithClientOAuth2Config(&deployengine.OAuth2Config{
+			ProviderBaseURL: providerBaseURL,
+			TokenEndpoint:   tokenEndpoint,
+			ClientID:        clientID,
+			ClientSecret:    clientSecret,
+		}),
+	}, nil
+}
+
+func signatureV1Options(confProvider *config.Provider) ([]deployengine.ClientOption, error) {
+	keyID, _ := confProvider.GetString("engine.auth.signatureV1.keyID")
+	secretKey, _ := confProvider.GetString("engine.auth.signatureV1.secretKey")
+	customHeaders, _ := confProvider.GetStringSlice("engine.auth.signatureV1.customHeaders")
+	if err := confProvider.ValueErrors(); err != nil {
+		return nil, err
+	}
+
+	if keyID == "" {
+		return nil, errMissingAuthConfig(AuthMethodCeleritySignatureV1, "engine.auth.signatureV1.keyID")
+	}
+
+	if secretKey == "" {
+		return nil, errMissingAuthConfig(AuthMethodCeleritySignatureV1, "engine.auth.signatureV1.secretKey")
+	}
+
+	return []deployengine.ClientOption{
+		deployengine.WithClientAuthMethod(deployengine.AuthMethodBluelinkSignatureV1),
+		deployengine.WithClientBluelinkSigv1KeyPair(&sigv1.KeyPair{
+			KeyID:     keyID,
+			SecretKey: secretKey,
+		}),
+		deployengine.WithClientBluelinkSigv1CustomHeaders(customHeaders),
+	}, nil
+}
+
+func errMissingAuthConfig(authMethod string, configName string) error {
+	return fmt.Errorf(
+		"the %q engine auth method requires the \"%s\" config value to be set",
+		authMethod,
+		configName,
 	)
 }
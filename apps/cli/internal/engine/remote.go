@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/config"
+)
+
+// IsRemote determines whether the CLI is configured to connect to
+// a deploy engine that runs on a different machine.
+// A remote deploy engine can not read blueprint files from the local
+// file system of the machine running the CLI.
+func IsRemote(confProvider *config.Provider) bool {
+	connectProtocol, _ := confProvider.GetString("connectProtocol")
+	if connectProtocol != "tcp" {
+		return false
+	}
+
+	endpoint, _ := confProvider.GetString("engine.endpoint")
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return true
+	}
+
+	host := endpointURL.Hostname()
+	if host == "localhost" {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/cleanup"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// NewCleanupHandler creates a new handler for triggering the cleanup
+// of data that is older than the retention period configured
+// for the deploy engine.
+func NewCleanupHandler(
+	deployEngine engine.DeployEngine,
+	opts *cleanup.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		targets, err := cleanup.Targets(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, target := range targets {
+			logger.Debug("triggering cleanup", zap.String("target", string(target)))
+			err := cleanup.Trigger(ctx, deployEngine, target)
+			if err != nil {
+				return engine.SimplifyError(err, logger)
+			}
+			fmt.Fprintf(writer, "Triggered cleanup of %s\n", target)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// NewDeployHandler creates a new deployment handler
+// for non-interactive environments.
+func NewDeployHandler(
+	deployEngine engine.DeployEngine,
+	opts *deploy.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		fmt.Fprintf(writer, "Deploying blueprint file: %s\n", opts.BlueprintFile)
+		staged, err := deploy.StageChanges(ctx, deployEngine, opts, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Change set: %s\n", staged.ChangesetID)
+
+		instance, err := deploy.Start(ctx, deployEngine, opts, staged, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Blueprint instance: %s\n\n", instance.InstanceID)
+
+		progress, err := streamInstanceEvents(ctx, deployEngine, instance.InstanceID, writer, logger)
+		if err != nil {
+			return err
+		}
+
+		if err := progress.Err(); err != nil {
+			return fmt.Errorf("deployment %w", err)
+		}
+
+		return nil
+	})
+}
+
+// streamInstanceEvents writes each event from a deployment or removal
+// process to the provided writer as it is received
+// and returns the final progress once the process has finished.
+func streamInstanceEvents(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	instanceID string,
+	writer io.Writer,
+	logger *zap.Logger,
+) (*deploy.Progress, error) {
+	streamTo := make(chan types.BlueprintInstanceEvent)
+	errChan := make(chan error)
+	err := deployEngine.StreamBlueprintInstanceEvents(
+		ctx,
+		instanceID,
+		streamTo,
+		errChan,
+	)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	progress := deploy.NewProgress()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-errChan:
+			if err != nil {
+				return nil, engine.SimplifyError(err, logger)
+			}
+		case event, open := <-streamTo:
+			if !open {
+				if progress.Finished() {
+					return progress, nil
+				}
+				return nil, fmt.Errorf(
+					"event stream for blueprint instance %q closed before the process finished",
+					instanceID,
+				)
+			}
+
+			element := progress.Apply(&event, time.Now())
+			if element != nil {
+				writeElementProgress(writer, element)
+			}
+
+			if progress.Finished() {
+				fmt.Fprintf(
+					writer,
+					"\nFinished with status: %s\n",
+					deploy.InstanceStatusName(progress.InstanceStatus()),
+				)
+				return progress, nil
+			}
+		}
+	}
+}
+
+func writeElementProgress(writer io.Writer, element *deploy.ElementProgress) {
+	fmt.Fprintf(
+		writer,
+		"  %-9s %-40s %-24s %s\n",
+		element.Kind,
+		element.Name,
+		element.Status,
+		element.Elapsed(time.Now()).Round(time.Millisecond),
+	)
+	for _, reason := range element.FailureReasons {
+		fmt.Fprintf(writer, "    - %s\n", reason)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/destroy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// NewDestroyHandler creates a new handler for destroying a blueprint
+// instance in non-interactive environments.
+// The provided reader is used to read the confirmation of the instance name
+// when a protected blueprint instance is being destroyed.
+func NewDestroyHandler(
+	deployEngine engine.DeployEngine,
+	opts *destroy.Options,
+	reader io.Reader,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		target, err := destroy.Prepare(ctx, deployEngine, opts, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Destroying blueprint instance: %s\n", target.InstanceName)
+
+		if target.Protected {
+			fmt.Fprintf(
+				writer,
+				"Blueprint instance %q is protected, enter the instance name to confirm: ",
+				target.InstanceName,
+			)
+			entered, err := bufio.NewReader(reader).ReadString('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+			fmt.Fprintln(writer)
+			if err := destroy.Confirm(target, entered); err != nil {
+				return err
+			}
+		}
+
+		staged, err := destroy.StageChanges(ctx, deployEngine, opts, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Change set: %s\n", staged.ChangesetID)
+
+		instance, err := destroy.Start(ctx, deployEngine, opts, staged, logger)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "Blueprint instance: %s\n\n", instance.InstanceID)
+
+		progress, err := streamInstanceEvents(ctx, deployEngine, instance.InstanceID, writer, logger)
+		if err != nil {
+			return err
+		}
+
+		if err := progress.Err(); err != nil {
+			return fmt.Errorf("destroy %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/instances"
+	"go.uber.org/zap"
+)
+
+// NewInstanceGetHandler creates a new handler for writing the current
+// state of a blueprint instance in the provided output format.
+func NewInstanceGetHandler(
+	deployEngine engine.DeployEngine,
+	instanceID string,
+	format instances.OutputFormat,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		instance, err := deployEngine.GetBlueprintInstance(ctx, instanceID)
+		if err != nil {
+			return engine.SimplifyError(err, logger)
+		}
+
+		if format == instances.OutputFormatText {
+			_, err := fmt.Fprint(writer, instances.RenderInstanceTree(instance))
+			return err
+		}
+
+		return instances.WriteStructured(writer, instance, format)
+	})
+}
+
+// NewInstanceExportsHandler creates a new handler for writing the exports
+// of a blueprint instance in the provided output format.
+// When an export path is provided, only the selected value is written,
+// scalar values are written without any formatting in the text format
+// so they can be used directly in scripts.
+func NewInstanceExportsHandler(
+	deployEngine engine.DeployEngine,
+	instanceID string,
+	exportPath string,
+	format instances.OutputFormat,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		exports, err := deployEngine.GetBlueprintInstanceExports(ctx, instanceID)
+		if err != nil {
+			return engine.SimplifyError(err, logger)
+		}
+
+		if exportPath == "" {
+			if format == instances.OutputFormatText {
+				rendered, err := instances.RenderExports(exports)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprint(writer, rendered)
+				return err
+			}
+
+			return instances.WriteStructured(writer, exports, format)
+		}
+
+		value, err := instances.SelectExport(exports, exportPath)
+		if err != nil {
+			return err
+		}
+
+		return writeExportValue(writer, value, format)
+	})
+}
+
+func writeExportValue(writer io.Writer, value *core.MappingNode, format instances.OutputFormat) error {
+	if format == instances.OutputFormatText {
+		formatted, err := instances.FormatValue(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, formatted)
+		return err
+	}
+
+	return instances.WriteStructured(writer, value, format)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"go.uber.org/zap"
+)
+
+// NewStageHandler creates a new change staging handler
+// for non-interactive environments.
+func NewStageHandler(
+	deployEngine engine.DeployEngine,
+	opts *stage.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		fmt.Fprintf(writer, "Staging changes for blueprint file: %s\n", opts.BlueprintFile)
+		payload, err := stage.CreateChangesetPayload(opts)
+		if err != nil {
+			return err
+		}
+
+		changeset, err := deployEngine.CreateChangeset(ctx, payload)
+		if err != nil {
+			return engine.SimplifyError(err, logger)
+		}
+		logger.Debug("change set created", zap.String("changesetId", changeset.ID))
+		fmt.Fprintf(writer, "Change set: %s\n\n", changeset.ID)
+
+		streamTo := make(chan types.ChangeStagingEvent)
+		errChan := make(chan error)
+		err = deployEngine.StreamChangeStagingEvents(
+			ctx,
+			changeset.ID,
+			streamTo,
+			errChan,
+		)
+		if err != nil {
+			return engine.SimplifyError(err, logger)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-errChan:
+				if err != nil {
+					return engine.SimplifyError(err, logger)
+				}
+			case event, open := <-streamTo:
+				if !open {
+					return fmt.Errorf(
+						"change staging stream for change set %q closed before staging was complete",
+						changeset.ID,
+					)
+				}
+
+				if completeChanges, isComplete := event.AsCompleteChanges(); isComplete {
+					summary := stage.SummariseChanges(completeChanges.Changes)
+					fmt.Fprintln(writer)
+					fmt.Fprintln(writer, "Change staging complete")
+					fmt.Fprintln(writer, summary.String())
+					return nil
+				}
+
+				writeChangeStagingEvent(writer, &event)
+			}
+		}
+	})
+}
+
+func writeChangeStagingEvent(writer io.Writer, event *types.ChangeStagingEvent) {
+	switch event.GetType() {
+	case types.ChangeStagingEventTypeResourceChanges:
+		resourceChanges, _ := event.AsResourceChanges()
+		action := stage.ResourceChangeAction(
+			&resourceChanges.Changes,
+			resourceChanges.New,
+			resourceChanges.Removed,
+		)
+		fmt.Fprintf(writer, "  resource  %-40s %s\n", resourceChanges.ResourceName, action)
+	case types.ChangeStagingEventTypeChildChanges:
+		childChanges, _ := event.AsChildChanges()
+		fmt.Fprintf(
+			writer,
+			"  child     %-40s %s\n",
+			childChanges.ChildBlueprintName,
+			stage.ChildChangeAction(childChanges),
+		)
+	case types.ChangeStagingEventTypeLinkChanges:
+		linkChanges, _ := event.AsLinkChanges()
+		fmt.Fprintf(
+			writer,
+			"  link      %-40s %s\n",
+			stage.LinkName(linkChanges.ResourceAName, linkChanges.ResourceBName),
+			stage.LinkChangeAction(linkChanges),
+		)
+	}
+}
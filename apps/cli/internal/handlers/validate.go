@@ -4,60 +4,139 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
-	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
 	"go.uber.org/zap"
 )
 
 // NewValidateHandler creates a new validation handler
 // for non-interactive environments.
+// Diagnostics are written as they are received for the text format
+// when a single blueprint file is validated, all other formats are written
+// once validation has finished as they represent a single document.
+// When multiple blueprint files are validated, diagnostics are grouped
+// by blueprint file and followed by a summary for each file and
+// for all files in the text format.
+// The handler fails with a validate.DiagnosticsError when any diagnostics
+// are at least as severe as the provided fail on level.
 func NewValidateHandler(
 	deployEngine engine.DeployEngine,
-	blueprintFile string,
+	opts *validate.Options,
 	writer io.Writer,
 	logger *zap.Logger,
 ) Handler {
 	return HandlerFunc(func(ctx context.Context) error {
-		fmt.Fprintf(writer, "Validating blueprint file: %s\n", blueprintFile)
-		blueprintValidation, err := deployEngine.CreateBlueprintValidation(
-			ctx,
-			&types.CreateBlueprintValidationPayload{
-				BlueprintDocumentInfo: types.BlueprintDocumentInfo{},
-			},
-			&types.CreateBlueprintValidationQuery{},
-		)
-		if err != nil {
-			return engine.SimplifyError(err, logger)
+		if len(opts.BlueprintFiles) == 1 {
+			return validateSingleFile(ctx, deployEngine, opts, writer, logger)
 		}
 
-		streamTo := make(chan types.BlueprintValidationEvent)
-		errChan := make(chan error)
-		err = deployEngine.StreamBlueprintValidationEvents(
-			ctx,
-			blueprintValidation.ID,
-			streamTo,
-			errChan,
-		)
+		return validateFiles(ctx, deployEngine, opts, writer, logger)
+	})
+}
+
+func validateSingleFile(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *validate.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) error {
+	blueprintFile := opts.BlueprintFiles[0]
+	format := opts.Format
+	if format == validate.FormatText {
+		fmt.Fprintf(writer, "Validating blueprint file: %s\n", blueprintFile)
+	}
+
+	var source *validate.Source
+	if format == validate.FormatText {
+		source = validate.LoadSource(blueprintFile)
+	}
+	result := validate.ValidateFile(
+		ctx,
+		deployEngine,
+		opts,
+		blueprintFile,
+		logger,
+		func(diagnostic *core.Diagnostic) {
+			if format == validate.FormatText {
+				fmt.Fprintln(writer, validate.DiagnosticWithSource(diagnostic, source, opts.Hyperlinks))
+			}
+		},
+	)
+	if result.Err != nil {
+		return result.Err
+	}
+
+	if format == validate.FormatText {
+		fmt.Fprintf(writer, "Validation complete with %s\n", result.Counts.String())
+	} else {
+		err := validate.WriteDiagnostics(writer, format, []*validate.FileResult{result})
 		if err != nil {
 			return err
 		}
+	}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case err := <-errChan:
-				if err != nil {
-					return err
-				}
-			case event, open := <-streamTo:
-				if !open {
-					fmt.Fprintln(writer, "Validation stream closed")
-					return nil
-				}
-				fmt.Fprintf(writer, "Received event: %+v\n", event)
+	return validate.CheckDiagnostics(result.Counts, opts.FailOn)
+}
+
+func validateFiles(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *validate.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) error {
+	format := opts.Format
+	onResult := func(*validate.FileResult) {}
+	if format == validate.FormatText {
+		fmt.Fprintf(
+			writer,
+			"Validating %d blueprint files with a parallelism of %d\n",
+			len(opts.BlueprintFiles),
+			opts.Parallelism,
+		)
+		// Diagnostics are written for each blueprint file as soon
+		// as it has been validated so they are not interleaved
+		// with the diagnostics for other files.
+		onResult = func(result *validate.FileResult) {
+			fmt.Fprintf(writer, "\n%s\n", result.BlueprintFile)
+			source := validate.LoadSource(result.BlueprintFile)
+			for _, diagnostic := range result.Diagnostics {
+				text := validate.DiagnosticWithSource(diagnostic, source, opts.Hyperlinks)
+				fmt.Fprintf(writer, "  %s\n", strings.ReplaceAll(text, "\n", "\n  "))
+			}
+			if result.Err != nil {
+				fmt.Fprintf(writer, "  validation failed: %s\n", result.Err)
+			} else {
+				fmt.Fprintf(writer, "  validation complete with %s\n", result.Counts.String())
 			}
 		}
-	})
+	}
+
+	results := validate.ValidateFiles(ctx, deployEngine, opts, logger, onResult)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if format == validate.FormatText {
+		fmt.Fprintln(writer, "\nSummary:")
+		for _, result := range results {
+			fmt.Fprintf(writer, "  %s\n", validate.ResultSummary(result))
+		}
+		fmt.Fprintf(
+			writer,
+			"Validation of %d blueprint files complete with %s\n",
+			len(results),
+			validate.TotalCounts(results).String(),
+		)
+	} else {
+		if err := validate.WriteDiagnostics(writer, format, results); err != nil {
+			return err
+		}
+	}
+
+	return validate.CheckResults(results, opts.FailOn)
 }
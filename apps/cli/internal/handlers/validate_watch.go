@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/watch"
+	"go.uber.org/zap"
+)
+
+// NewValidateWatchHandler creates a new validation handler for non-interactive
+// environments that validates the blueprint again each time the blueprint file,
+// its child blueprints or the deploy config files change.
+// Each validation is carried out in the same way as the handler created by
+// NewValidateHandler, failed validations are reported and the handler keeps
+// watching for changes until the provided context is cancelled.
+func NewValidateWatchHandler(
+	deployEngine engine.DeployEngine,
+	opts *validate.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) Handler {
+	return HandlerFunc(func(ctx context.Context) error {
+		watcher, err := watch.New(watch.DefaultDebounce)
+		if err != nil {
+			return err
+		}
+		defer watcher.Close()
+
+		for {
+			files, err := validate.WatchedFiles(opts)
+			if err != nil {
+				logger.Debug("failed to resolve child blueprints to watch", zap.Error(err))
+			}
+			if err := watcher.SetFiles(files); err != nil {
+				return err
+			}
+
+			err = validateOnce(ctx, deployEngine, opts, writer, logger)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				fmt.Fprintf(writer, "%s\n", err)
+			}
+
+			fmt.Fprintln(writer, "\nWatching for changes...")
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-watcher.Errors():
+				return err
+			case changed := <-watcher.Changes():
+				fmt.Fprintf(writer, "\nChange detected in %s\n", strings.Join(changed, ", "))
+			}
+		}
+	})
+}
+
+func validateOnce(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *validate.Options,
+	writer io.Writer,
+	logger *zap.Logger,
+) error {
+	runOpts := *opts
+	if opts.LoadDeployConfig != nil {
+		deployConfig, err := opts.LoadDeployConfig()
+		if err != nil {
+			return err
+		}
+		runOpts.DeployConfig = deployConfig
+	}
+
+	return NewValidateHandler(deployEngine, &runOpts, writer, logger).Handle(ctx)
+}
@@ -0,0 +1,107 @@
+package instances
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+)
+
+var (
+	exportPathSegmentPattern = regexp.MustCompile(`^([^.\[\]]+)((?:\[\d+\])*)$`)
+	exportPathIndexPattern   = regexp.MustCompile(`\[(\d+)\]`)
+)
+
+// SelectExport selects a single value from the exports of a blueprint instance
+// with a dotted path where the first segment is the export name and the rest of the path
+// is used to select a value within the export.
+// For example, "api.endpoints[0].url" would select the "url" field of the first
+// item in the "endpoints" array of the "api" export.
+func SelectExport(exports map[string]*state.ExportState, path string) (*core.MappingNode, error) {
+	segments := strings.Split(path, ".")
+	exportName, indices, err := parseExportPathSegment(path, segments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	export, hasExport := exports[exportName]
+	if !hasExport || export == nil {
+		return nil, fmt.Errorf("export %q not found in blueprint instance", exportName)
+	}
+
+	current, err := selectItems(path, export.Value, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range segments[1:] {
+		fieldName, indices, err := parseExportPathSegment(path, segment)
+		if err != nil {
+			return nil, err
+		}
+
+		if current == nil || current.Fields == nil {
+			return nil, fmt.Errorf("no export value found at path %q", path)
+		}
+
+		current, err = selectItems(path, current.Fields[fieldName], indices)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("no export value found at path %q", path)
+	}
+
+	return current, nil
+}
+
+func parseExportPathSegment(path string, segment string) (string, []int, error) {
+	match := exportPathSegmentPattern.FindStringSubmatch(segment)
+	if match == nil {
+		return "", nil, fmt.Errorf("invalid export path %q", path)
+	}
+
+	indices := []int{}
+	for _, indexMatch := range exportPathIndexPattern.FindAllStringSubmatch(match[2], -1) {
+		index, err := strconv.Atoi(indexMatch[1])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid export path %q: %w", path, err)
+		}
+		indices = append(indices, index)
+	}
+
+	return match[1], indices, nil
+}
+
+func selectItems(path string, node *core.MappingNode, indices []int) (*core.MappingNode, error) {
+	current := node
+	for _, index := range indices {
+		if current == nil || index >= len(current.Items) {
+			return nil, fmt.Errorf("no export value found at path %q", path)
+		}
+		current = current.Items[index]
+	}
+
+	return current, nil
+}
+
+// FormatValue produces a plain text representation of an export value.
+// Scalar values are written as they are so the output can be used directly
+// in scripts, complex values are written as JSON.
+func FormatValue(value *core.MappingNode) (string, error) {
+	if core.IsScalarMappingNode(value) {
+		return value.Scalar.ToString(), nil
+	}
+
+	jsonBytes, err := value.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
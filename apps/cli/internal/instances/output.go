@@ -0,0 +1,79 @@
+package instances
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is the format used to write blueprint instance
+// information to the output of a command.
+type OutputFormat string
+
+const (
+	// OutputFormatText writes a human-readable representation.
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON writes a JSON representation.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatYAML writes a YAML representation.
+	OutputFormatYAML OutputFormat = "yaml"
+)
+
+var (
+	// SupportedOutputFormats is a list of all the output formats
+	// supported for blueprint instance information.
+	SupportedOutputFormats = []OutputFormat{
+		OutputFormatText,
+		OutputFormatJSON,
+		OutputFormatYAML,
+	}
+)
+
+// ParseOutputFormat parses and validates an output format provided by the user.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	format := OutputFormat(value)
+	if slices.Contains(SupportedOutputFormats, format) {
+		return format, nil
+	}
+
+	supported := []string{}
+	for _, supportedFormat := range SupportedOutputFormats {
+		supported = append(supported, fmt.Sprintf("%q", supportedFormat))
+	}
+
+	return "", fmt.Errorf(
+		"unsupported output format %q, must be one of %s",
+		value,
+		strings.Join(supported, ", "),
+	)
+}
+
+// WriteStructured writes the provided value as JSON or YAML.
+// Values are always converted via their JSON representation so that
+// field names are consistent between the two formats.
+func WriteStructured(writer io.Writer, value any, format OutputFormat) error {
+	jsonBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if format == OutputFormatJSON {
+		_, err := fmt.Fprintln(writer, string(jsonBytes))
+		return err
+	}
+
+	var generic any
+	err = json.Unmarshal(jsonBytes, &generic)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(generic)
+}
@@ -0,0 +1,224 @@
+package instances
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/state"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+)
+
+var (
+	headingStyle          = lipgloss.NewStyle().Bold(true)
+	sectionStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#4f46e5"))
+	mutedStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	statusInProgressStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#2563eb"))
+	statusSuccessStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#16a34a"))
+	statusFailureStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626"))
+	statusRolledBackStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f97316"))
+)
+
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+// RenderInstanceTree renders the state of a blueprint instance as a tree
+// of resources, links, child blueprints and exports with their statuses.
+func RenderInstanceTree(instance *state.InstanceState) string {
+	sb := strings.Builder{}
+	sb.WriteString(headingStyle.Render(instanceLabel(instance.InstanceName, instance.InstanceID)))
+	sb.WriteString(" ")
+	sb.WriteString(renderInstanceStatus(instance))
+	sb.WriteString("\n")
+
+	nodes := instanceTreeNodes(instance)
+	for i, node := range nodes {
+		writeTreeNode(&sb, node, "", i == len(nodes)-1)
+	}
+
+	return sb.String()
+}
+
+// RenderExports renders the exports of a blueprint instance
+// with their types and values.
+func RenderExports(exports map[string]*state.ExportState) (string, error) {
+	sb := strings.Builder{}
+	for _, name := range sortedKeys(exports) {
+		label, err := exportLabel(name, exports[name])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(label)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+func instanceTreeNodes(instance *state.InstanceState) []*treeNode {
+	nodes := []*treeNode{}
+
+	if len(instance.Resources) > 0 {
+		resources := &treeNode{label: sectionStyle.Render("resources")}
+		for _, resource := range sortedResources(instance.Resources) {
+			resources.children = append(resources.children, resourceTreeNode(resource))
+		}
+		nodes = append(nodes, resources)
+	}
+
+	if len(instance.Links) > 0 {
+		links := &treeNode{label: sectionStyle.Render("links")}
+		for _, name := range sortedKeys(instance.Links) {
+			links.children = append(links.children, linkTreeNode(instance.Links[name]))
+		}
+		nodes = append(nodes, links)
+	}
+
+	if len(instance.ChildBlueprints) > 0 {
+		children := &treeNode{label: sectionStyle.Render("children")}
+		for _, name := range sortedKeys(instance.ChildBlueprints) {
+			child := instance.ChildBlueprints[name]
+			children.children = append(children.children, &treeNode{
+				label:    fmt.Sprintf("%s %s", instanceLabel(name, child.InstanceID), renderInstanceStatus(child)),
+				children: instanceTreeNodes(child),
+			})
+		}
+		nodes = append(nodes, children)
+	}
+
+	if len(instance.Exports) > 0 {
+		exports := &treeNode{label: sectionStyle.Render("exports")}
+		for _, name := range sortedKeys(instance.Exports) {
+			label, err := exportLabel(name, instance.Exports[name])
+			if err != nil {
+				label = fmt.Sprintf("%s %s", name, mutedStyle.Render("(unable to render value)"))
+			}
+			exports.children = append(exports.children, &treeNode{label: label})
+		}
+		nodes = append(nodes, exports)
+	}
+
+	return nodes
+}
+
+func resourceTreeNode(resource *state.ResourceState) *treeNode {
+	category := deploy.ResourceStatusCategory(resource.Status)
+	node := &treeNode{
+		label: fmt.Sprintf(
+			"%s %s %s",
+			resource.Name,
+			mutedStyle.Render("("+resource.Type+")"),
+			categoryStyle(category).Render(deploy.ResourceStatusName(resource.Status)),
+		),
+	}
+	if resource.Drifted {
+		node.label += " " + statusRolledBackStyle.Render("drifted")
+	}
+	node.children = failureReasonNodes(resource.FailureReasons)
+	return node
+}
+
+func linkTreeNode(link *state.LinkState) *treeNode {
+	category := deploy.LinkStatusCategory(link.Status)
+	return &treeNode{
+		label: fmt.Sprintf(
+			"%s %s",
+			link.Name,
+			categoryStyle(category).Render(deploy.LinkStatusName(link.Status)),
+		),
+		children: failureReasonNodes(link.FailureReasons),
+	}
+}
+
+func failureReasonNodes(failureReasons []string) []*treeNode {
+	nodes := []*treeNode{}
+	for _, reason := range failureReasons {
+		nodes = append(nodes, &treeNode{label: statusFailureStyle.Render(reason)})
+	}
+	return nodes
+}
+
+func exportLabel(name string, export *state.ExportState) (string, error) {
+	if export == nil || export.Value == nil {
+		return fmt.Sprintf("%s %s", name, mutedStyle.Render("(no value)")), nil
+	}
+
+	value, err := FormatValue(export.Value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%s %s = %s",
+		name,
+		mutedStyle.Render("("+string(export.Type)+")"),
+		value,
+	), nil
+}
+
+func instanceLabel(name string, id string) string {
+	if name == "" {
+		return id
+	}
+
+	return fmt.Sprintf("%s %s", name, mutedStyle.Render("(id: "+id+")"))
+}
+
+func renderInstanceStatus(instance *state.InstanceState) string {
+	category := deploy.InstanceStatusCategory(instance.Status)
+	return categoryStyle(category).Render(deploy.InstanceStatusName(instance.Status))
+}
+
+func writeTreeNode(sb *strings.Builder, node *treeNode, prefix string, isLast bool) {
+	branch := "├── "
+	childPrefix := prefix + "│   "
+	if isLast {
+		branch = "└── "
+		childPrefix = prefix + "    "
+	}
+
+	sb.WriteString(prefix)
+	sb.WriteString(branch)
+	sb.WriteString(node.label)
+	sb.WriteString("\n")
+
+	for i, child := range node.children {
+		writeTreeNode(sb, child, childPrefix, i == len(node.children)-1)
+	}
+}
+
+func categoryStyle(category deploy.StatusCategory) lipgloss.Style {
+	switch category {
+	case deploy.StatusCategorySuccess:
+		return statusSuccessStyle
+	case deploy.StatusCategoryFailure:
+		return statusFailureStyle
+	case deploy.StatusCategoryRolledBack:
+		return statusRolledBackStyle
+	default:
+		return statusInProgressStyle
+	}
+}
+
+func sortedResources(resources map[string]*state.ResourceState) []*state.ResourceState {
+	sorted := []*state.ResourceState{}
+	for _, resource := range resources {
+		sorted = append(sorted, resource)
+	}
+	slices.SortFunc(sorted, func(a, b *state.ResourceState) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+func sortedKeys[Value any](values map[string]Value) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
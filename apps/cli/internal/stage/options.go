@@ -0,0 +1,78 @@
+package stage
+
+import (
+	"errors"
+
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+)
+
+// Options holds the user-provided options for staging changes
+// for a blueprint instance.
+type Options struct {
+	// BlueprintFile is the location of the blueprint file
+	// to stage changes for, this can be a local path or a URI
+	// for a remote source such as "s3://bucket/app.blueprint.yaml".
+	BlueprintFile string
+	// InstanceID is the ID of an existing blueprint instance
+	// to stage changes for.
+	InstanceID string
+	// InstanceName is the user-defined name of an existing
+	// blueprint instance to stage changes for.
+	InstanceName string
+	// Destroy determines whether the change set should be
+	// created for destroying an existing blueprint instance.
+	Destroy bool
+	// DeployConfig is the config loaded from the deploy config file
+	// that is sent to the deploy engine, this is nil when there is
+	// no deploy config file.
+	DeployConfig *types.BlueprintOperationConfig
+	// RemoteEngine should be set when the deploy engine runs on
+	// a different machine and can not read local blueprint files.
+	RemoteEngine bool
+}
+
+// CreateChangesetPayload builds the payload for a request to the deploy engine
+// to create a change set from the provided options.
+func CreateChangesetPayload(opts *Options) (*types.CreateChangesetPayload, error) {
+	if opts.Destroy && opts.InstanceID == "" && opts.InstanceName == "" {
+		return nil, ErrDestroyWithoutInstance
+	}
+
+	payload := &types.CreateChangesetPayload{
+		InstanceID:   opts.InstanceID,
+		InstanceName: instanceName(opts),
+		Destroy:      opts.Destroy,
+		Config:       opts.DeployConfig,
+	}
+
+	// A blueprint document is not required when staging changes
+	// for destroying an existing blueprint instance.
+	if opts.BlueprintFile != "" {
+		docInfo, err := blueprint.ResolveDocumentInfo(opts.BlueprintFile, opts.RemoteEngine)
+		if err != nil {
+			return nil, err
+		}
+		payload.BlueprintDocumentInfo = docInfo
+	}
+
+	return payload, nil
+}
+
+func instanceName(opts *Options) string {
+	// The deploy engine expects only one of the instance ID or name
+	// to be provided, the ID takes precedence when both are set.
+	if opts.InstanceID != "" {
+		return ""
+	}
+
+	return opts.InstanceName
+}
+
+var (
+	// ErrDestroyWithoutInstance is returned when changes are to be staged
+	// for destroying a blueprint instance without an instance ID or name.
+	ErrDestroyWithoutInstance = errors.New(
+		"an instance ID or name must be provided to stage changes for destroying a blueprint instance",
+	)
+)
@@ -0,0 +1,82 @@
+package stage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// Result holds the outcome of a completed change staging process.
+type Result struct {
+	// ChangesetID is the ID of the change set that was created.
+	ChangesetID string
+	// InstanceID is the ID of the existing blueprint instance that
+	// changes were staged for, this is empty for new blueprint instances.
+	InstanceID string
+	// Changes is the full set of changes that were staged.
+	Changes *changes.BlueprintChanges
+}
+
+// Run creates a change set with the provided options and blocks until
+// the deploy engine reports that change staging is complete.
+// This is useful for commands such as deploy and destroy that need a change set
+// before they can start but do not need to render each change staging event.
+func Run(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	logger *zap.Logger,
+) (*Result, error) {
+	payload, err := CreateChangesetPayload(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changeset, err := deployEngine.CreateChangeset(ctx, payload)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+	logger.Debug("change set created", zap.String("changesetId", changeset.ID))
+
+	streamTo := make(chan types.ChangeStagingEvent)
+	errChan := make(chan error)
+	err = deployEngine.StreamChangeStagingEvents(
+		ctx,
+		changeset.ID,
+		streamTo,
+		errChan,
+	)
+	if err != nil {
+		return nil, engine.SimplifyError(err, logger)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-errChan:
+			if err != nil {
+				return nil, engine.SimplifyError(err, logger)
+			}
+		case event, open := <-streamTo:
+			if !open {
+				return nil, fmt.Errorf(
+					"change staging stream for change set %q closed before staging was complete",
+					changeset.ID,
+				)
+			}
+
+			if completeChanges, isComplete := event.AsCompleteChanges(); isComplete {
+				return &Result{
+					ChangesetID: changeset.ID,
+					InstanceID:  changeset.InstanceID,
+					Changes:     completeChanges.Changes,
+				}, nil
+			}
+		}
+	}
+}
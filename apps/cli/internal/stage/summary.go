@@ -0,0 +1,187 @@
+package stage
+
+import (
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/changes"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/provider"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// ChangeAction is an enum that represents the action
+// that will be taken for an element of a blueprint instance
+// when a change set is deployed.
+type ChangeAction string
+
+const (
+	// ChangeActionCreate is used for elements that will be created.
+	ChangeActionCreate ChangeAction = "create"
+	// ChangeActionUpdate is used for elements that will be updated in place.
+	ChangeActionUpdate ChangeAction = "update"
+	// ChangeActionRecreate is used for elements that must be
+	// removed and created again to apply the changes.
+	ChangeActionRecreate ChangeAction = "recreate"
+	// ChangeActionRemove is used for elements that will be removed.
+	ChangeActionRemove ChangeAction = "remove"
+	// ChangeActionNoChange is used for elements that will be left as they are.
+	ChangeActionNoChange ChangeAction = "no change"
+)
+
+// Summary holds the number of creates, updates, recreates and removals
+// in a set of blueprint changes, including changes in child blueprints.
+type Summary struct {
+	Creates   int
+	Updates   int
+	Recreates int
+	Removals  int
+}
+
+// Total returns the total number of changes in the summary.
+func (s *Summary) Total() int {
+	return s.Creates + s.Updates + s.Recreates + s.Removals
+}
+
+// String produces a human-readable overview of the summary.
+func (s *Summary) String() string {
+	return fmt.Sprintf(
+		"%d to create, %d to update, %d to recreate, %d to remove",
+		s.Creates,
+		s.Updates,
+		s.Recreates,
+		s.Removals,
+	)
+}
+
+// SummariseChanges counts the creates, updates, recreates and removals
+// for resources, links and child blueprints in the provided changes.
+func SummariseChanges(blueprintChanges *changes.BlueprintChanges) *Summary {
+	summary := &Summary{}
+	if blueprintChanges == nil {
+		return summary
+	}
+
+	addBlueprintChanges(summary, blueprintChanges)
+	return summary
+}
+
+func addBlueprintChanges(summary *Summary, blueprintChanges *changes.BlueprintChanges) {
+	for _, resourceChanges := range blueprintChanges.NewResources {
+		summary.Creates += 1
+		summary.Creates += len(resourceChanges.NewOutboundLinks)
+	}
+
+	for _, resourceChanges := range blueprintChanges.ResourceChanges {
+		addResourceChanges(summary, &resourceChanges)
+	}
+
+	summary.Removals += len(blueprintChanges.RemovedResources)
+	summary.Removals += len(blueprintChanges.RemovedLinks)
+
+	for _, newChild := range blueprintChanges.NewChildren {
+		addNewBlueprintDefinition(summary, &newChild)
+	}
+
+	for _, childChanges := range blueprintChanges.ChildChanges {
+		summary.Updates += 1
+		addBlueprintChanges(summary, &childChanges)
+	}
+
+	summary.Recreates += len(blueprintChanges.RecreateChildren)
+	summary.Removals += len(blueprintChanges.RemovedChildren)
+}
+
+func addResourceChanges(summary *Summary, resourceChanges *provider.Changes) {
+	switch ResourceChangeAction(resourceChanges, false, false) {
+	case ChangeActionRecreate:
+		summary.Recreates += 1
+	case ChangeActionUpdate:
+		summary.Updates += 1
+	}
+
+	summary.Creates += len(resourceChanges.NewOutboundLinks)
+	summary.Updates += len(resourceChanges.OutboundLinkChanges)
+	summary.Removals += len(resourceChanges.RemovedOutboundLinks)
+}
+
+func addNewBlueprintDefinition(summary *Summary, definition *changes.NewBlueprintDefinition) {
+	summary.Creates += 1
+	summary.Creates += len(definition.NewResources)
+	for _, newChild := range definition.NewChildren {
+		addNewBlueprintDefinition(summary, &newChild)
+	}
+}
+
+// ResourceChangeAction determines the action that will be taken
+// for a resource based on the changes computed for it during change staging.
+func ResourceChangeAction(resourceChanges *provider.Changes, isNew bool, isRemoved bool) ChangeAction {
+	if isNew {
+		return ChangeActionCreate
+	}
+
+	if isRemoved {
+		return ChangeActionRemove
+	}
+
+	if resourceChanges == nil {
+		return ChangeActionNoChange
+	}
+
+	if resourceChanges.MustRecreate {
+		return ChangeActionRecreate
+	}
+
+	if len(resourceChanges.ModifiedFields) > 0 ||
+		len(resourceChanges.NewFields) > 0 ||
+		len(resourceChanges.RemovedFields) > 0 ||
+		len(resourceChanges.FieldChangesKnownOnDeploy) > 0 {
+		return ChangeActionUpdate
+	}
+
+	return ChangeActionNoChange
+}
+
+// ChildChangeAction determines the action that will be taken
+// for a child blueprint based on the changes computed for it during change staging.
+func ChildChangeAction(childChanges *types.ChildChangesEventData) ChangeAction {
+	if childChanges.New {
+		return ChangeActionCreate
+	}
+
+	if childChanges.Removed {
+		return ChangeActionRemove
+	}
+
+	if SummariseChanges(&childChanges.Changes).Total() > 0 {
+		return ChangeActionUpdate
+	}
+
+	return ChangeActionNoChange
+}
+
+// LinkChangeAction determines the action that will be taken
+// for a link between two resources based on the changes computed for it
+// during change staging.
+func LinkChangeAction(linkChanges *types.LinkChangesEventData) ChangeAction {
+	if linkChanges.New {
+		return ChangeActionCreate
+	}
+
+	if linkChanges.Removed {
+		return ChangeActionRemove
+	}
+
+	if len(linkChanges.Changes.ModifiedFields) > 0 ||
+		len(linkChanges.Changes.NewFields) > 0 ||
+		len(linkChanges.Changes.RemovedFields) > 0 ||
+		len(linkChanges.Changes.FieldChangesKnownOnDeploy) > 0 {
+		return ChangeActionUpdate
+	}
+
+	return ChangeActionNoChange
+}
+
+// LinkName produces the logical name of a link between two resources
+// in the same format used by the deploy engine.
+func LinkName(resourceAName string, resourceBName string) string {
+	return resourceAName + "::" + resourceBName
+}
@@ -0,0 +1,285 @@
+package configui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+)
+
+const listHeight = 8
+
+var (
+	titleStyle        = lipgloss.NewStyle().MarginLeft(2)
+	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
+	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
+	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
+	errorStyle        = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+	answerStyle       = lipgloss.NewStyle().MarginLeft(2)
+)
+
+// Value is a config value provided in the config init wizard.
+type Value struct {
+	ConfigName string
+	Value      string
+}
+
+// InitConfigModel is the model for the wizard that asks for the connection
+// and auth settings to write to a new CLI config file.
+type InitConfigModel struct {
+	steps     []*step
+	current   int
+	answers   map[string]string
+	values    []Value
+	list      list.Model
+	input     textinput.Model
+	styles    *styles.CelerityStyles
+	validate  func(configName string, value string) error
+	width     int
+	err       error
+	done      bool
+	cancelled bool
+}
+
+func (m InitConfigModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m InitConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.list.SetWidth(msg.Width)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			return m, tea.Quit
+		case "enter":
+			return m.submit()
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.steps[m.current].kind == stepKindSelect {
+		m.list, cmd = m.list.Update(msg)
+	} else {
+		m.input, cmd = m.input.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m InitConfigModel) submit() (tea.Model, tea.Cmd) {
+	currentStep := m.steps[m.current]
+	value := ""
+	if currentStep.kind == stepKindSelect {
+		selected, ok := m.list.SelectedItem().(optionItem)
+		if !ok {
+			return m, nil
+		}
+		value = selected.value
+	} else {
+		value = strings.TrimSpace(m.input.Value())
+		if value == "" {
+			value = currentStep.defaultValue
+		}
+	}
+
+	if value == "" && !currentStep.optional {
+		m.err = fmt.Errorf("a value is required")
+		return m, nil
+	}
+
+	if value != "" {
+		if err := m.validate(currentStep.configName, value); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.values = append(m.values, Value{ConfigName: currentStep.configName, Value: value})
+	}
+
+	m.err = nil
+	m.answers[currentStep.configName] = value
+	m.steps = initSteps(m.answers)
+	m.current += 1
+	if m.current >= len(m.steps) {
+		m.done = true
+		return m, tea.Quit
+	}
+
+	return m, m.prepareStep()
+}
+
+// prepareStep sets up the list or text input for the current step.
+func (m *InitConfigModel) prepareStep() tea.Cmd {
+	currentStep := m.steps[m.current]
+	if currentStep.kind == stepKindSelect {
+		m.list = newOptionList(currentStep)
+		if m.width > 0 {
+			m.list.SetWidth(m.width)
+		}
+		return nil
+	}
+
+	m.input.Reset()
+	m.input.Placeholder = currentStep.placeholder
+	if currentStep.defaultValue != "" {
+		m.input.Placeholder = currentStep.defaultValue
+	}
+	m.input.EchoMode = textinput.EchoNormal
+	if currentStep.kind == stepKindSecret {
+		m.input.EchoMode = textinput.EchoPassword
+	}
+	return m.input.Focus()
+}
+
+func (m InitConfigModel) View() string {
+	if m.cancelled {
+		return ""
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	for _, value := range m.values {
+		sb.WriteString(answerStyle.Render(fmt.Sprintf(
+			"%s: %s",
+			value.ConfigName,
+			m.styles.Selected.Render(displayAnswer(m.steps, value)),
+		)))
+		sb.WriteString("\n")
+	}
+
+	if m.done {
+		return sb.String()
+	}
+
+	currentStep := m.steps[m.current]
+	sb.WriteString("\n")
+	if currentStep.kind == stepKindSelect {
+		sb.WriteString(m.list.View())
+	} else {
+		sb.WriteString(titleStyle.Render(currentStep.prompt))
+		sb.WriteString("\n\n  ")
+		sb.WriteString(m.input.View())
+		sb.WriteString("\n")
+	}
+
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(errorStyle.Render(m.err.Error()))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// Values returns the config values provided in the wizard in the
+// order they were provided, optional values that were skipped are not included.
+func (m InitConfigModel) Values() []Value {
+	return m.values
+}
+
+// Cancelled reports whether the user quit the wizard
+// before providing all of the config values.
+func (m InitConfigModel) Cancelled() bool {
+	return m.cancelled || !m.done
+}
+
+// NewInitConfigModel creates a new model for the config init wizard,
+// the validate function is used to check each value as it is provided.
+func NewInitConfigModel(
+	validate func(configName string, value string) error,
+	celerityStyles *styles.CelerityStyles,
+) *InitConfigModel {
+	input := textinput.New()
+	input.PromptStyle = celerityStyles.Selectable
+	input.TextStyle = celerityStyles.Selected
+	input.CharLimit = 1024
+	input.Width = 80
+
+	answers := map[string]string{}
+	steps := initSteps(answers)
+	model := &InitConfigModel{
+		steps:    steps,
+		answers:  answers,
+		input:    input,
+		styles:   celerityStyles,
+		validate: validate,
+	}
+	model.prepareStep()
+	return model
+}
+
+func displayAnswer(steps []*step, value Value) string {
+	for _, step := range steps {
+		if step.configName == value.ConfigName && step.kind == stepKindSecret {
+			return "********"
+		}
+	}
+	return value.Value
+}
+
+type optionItem struct {
+	option
+}
+
+func (i optionItem) FilterValue() string {
+	return ""
+}
+
+type itemDelegate struct{}
+
+func (d itemDelegate) Height() int {
+	return 1
+}
+
+func (d itemDelegate) Spacing() int {
+	return 0
+}
+
+func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(optionItem)
+	if !ok {
+		return
+	}
+
+	str := fmt.Sprintf("%d. %s", index+1, i.label)
+
+	fn := itemStyle.Render
+	if index == m.Index() {
+		fn = func(s ...string) string {
+			return selectedItemStyle.Render("> " + strings.Join(s, " "))
+		}
+	}
+
+	fmt.Fprint(w, fn(str))
+}
+
+func newOptionList(currentStep *step) list.Model {
+	items := make([]list.Item, len(currentStep.options))
+	for i, option := range currentStep.options {
+		items[i] = optionItem{option}
+	}
+
+	const defaultWidth = 20
+
+	optionList := list.New(items, itemDelegate{}, defaultWidth, listHeight)
+	optionList.Title = currentStep.prompt
+	optionList.SetShowStatusBar(false)
+	optionList.SetFilteringEnabled(false)
+	optionList.Styles.Title = titleStyle
+	optionList.Styles.PaginationStyle = paginationStyle
+	optionList.Styles.HelpStyle = helpStyle
+	return optionList
+}
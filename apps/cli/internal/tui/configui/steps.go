@@ -0,0 +1,163 @@
+package configui
+
+import (
+	deployengine "github.com/newstack-cloud/bluelink/libs/deploy-engine-client"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+)
+
+type stepKind int
+
+const (
+	// Step where the user selects one of a list of options.
+	stepKindSelect stepKind = iota
+	// Step where the user enters a value.
+	stepKindInput
+	// Step where the user enters a secret value,
+	// the value is hidden as it is typed.
+	stepKindSecret
+)
+
+type option struct {
+	value string
+	label string
+}
+
+// step is a single prompt in the config init wizard
+// that provides the value for a config value.
+type step struct {
+	configName   string
+	prompt       string
+	kind         stepKind
+	options      []option
+	defaultValue string
+	placeholder  string
+	// optional steps can be skipped by submitting an empty value.
+	optional bool
+}
+
+const (
+	secretPlaceholder = "${env:NAME}, ${file:path} or ${cmd:command}"
+)
+
+// initSteps determines the steps of the wizard based on the answers
+// provided so far, as answers to earlier steps determine which
+// connection and auth settings are needed.
+func initSteps(answers map[string]string) []*step {
+	steps := []*step{
+		{
+			configName: "connectProtocol",
+			prompt:     "How do you want to connect to the deploy engine?",
+			kind:       stepKindSelect,
+			options: []option{
+				{value: "unix", label: "Unix socket (local deploy engine)"},
+				{value: "tcp", label: "TCP (local or remote deploy engine)"},
+			},
+		},
+	}
+
+	switch answers["connectProtocol"] {
+	case "unix":
+		steps = append(steps, &step{
+			configName:   "engine.unixSocket",
+			prompt:       "Enter the path of the deploy engine unix socket:",
+			kind:         stepKindInput,
+			defaultValue: deployengine.DefaultUnixDomainSocket,
+		})
+	case "tcp":
+		steps = append(steps, &step{
+			configName:   "engine.endpoint",
+			prompt:       "Enter the endpoint of the deploy engine API:",
+			kind:         stepKindInput,
+			defaultValue: deployengine.DefaultEndpoint,
+		})
+	}
+
+	steps = append(steps, &step{
+		configName: "engine.authMethod",
+		prompt:     "How do you want to authenticate with the deploy engine?",
+		kind:       stepKindSelect,
+		options: []option{
+			{value: engine.AuthMethodNone, label: "None (local deploy engine)"},
+			{value: engine.AuthMethodAPIKey, label: "API key"},
+			{value: engine.AuthMethodOAuth2, label: "OAuth2 client credentials"},
+			{value: engine.AuthMethodCeleritySignatureV1, label: "Celerity Signature v1"},
+		},
+	})
+
+	switch answers["engine.authMethod"] {
+	case engine.AuthMethodAPIKey:
+		steps = append(steps, &step{
+			configName: "engine.auth.apiKey",
+			prompt: "Enter the API key or a reference to it, " +
+				"leave empty to use the CELERITY_CLI_ENGINE_API_KEY environment variable:",
+			kind:        stepKindSecret,
+			placeholder: secretPlaceholder,
+			optional:    true,
+		})
+	case engine.AuthMethodOAuth2:
+		steps = append(steps, oauth2Steps(answers)...)
+	case engine.AuthMethodCeleritySignatureV1:
+		steps = append(
+			steps,
+			&step{
+				configName: "engine.auth.signatureV1.keyID",
+				prompt:     "Enter the key ID:",
+				kind:       stepKindInput,
+			},
+			&step{
+				configName: "engine.auth.signatureV1.secretKey",
+				prompt: "Enter the secret key or a reference to it, " +
+					"leave empty to use the CELERITY_CLI_ENGINE_SIGNATURE_V1_SECRET_KEY environment variable:",
+				kind:        stepKindSecret,
+				placeholder: secretPlaceholder,
+				optional:    true,
+			},
+		)
+	}
+
+	return append(steps, &step{
+		configName:   "deployConfigFile",
+		prompt:       "Enter the path of the deploy config file:",
+		kind:         stepKindInput,
+		defaultValue: "celerity.deploy.json",
+	})
+}
+
+func oauth2Steps(answers map[string]string) []*step {
+	steps := []*step{
+		{
+			configName:  "engine.auth.oauth2.tokenEndpoint",
+			prompt:      "Enter the token endpoint of the OAuth2 provider, leave empty to discover it from the provider base URL:",
+			kind:        stepKindInput,
+			placeholder: "https://auth.example.com/oauth2/token",
+			optional:    true,
+		},
+	}
+
+	if _, hasTokenEndpoint := answers["engine.auth.oauth2.tokenEndpoint"]; hasTokenEndpoint &&
+		answers["engine.auth.oauth2.tokenEndpoint"] == "" {
+		steps = append(steps, &step{
+			configName:  "engine.auth.oauth2.providerBaseURL",
+			prompt:      "Enter the base URL of the OAuth2 or OIDC provider:",
+			kind:        stepKindInput,
+			placeholder: "https://auth.example.com",
+		})
+	}
+
+	return append(
+		steps,
+		&step{
+			configName: "engine.auth.oauth2.clientID",
+			prompt:     "Enter the client ID:",
+			kind:       stepKindInput,
+		},
+		&step{
+			configName: "engine.auth.oauth2.clientSecret",
+			prompt: "Enter the client secret or a reference to it, " +
+				"leave empty to use the CELERITY_CLI_ENGINE_OAUTH2_CLIENT_SECRET environment variable:",
+			kind:        stepKindSecret,
+			placeholder: secretPlaceholder,
+			optional:    true,
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package deployui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+func stageChangesCmd(model DeployModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		staged, err := deploy.StageChanges(context.TODO(), model.engine, model.opts, logger)
+		if err != nil {
+			return DeployErrMsg{err}
+		}
+
+		return ChangesStagedMsg{staged: staged}
+	}
+}
+
+func startDeploymentCmd(model DeployModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		instance, err := deploy.Start(context.TODO(), model.engine, model.opts, model.staged, logger)
+		if err != nil {
+			return DeployErrMsg{err}
+		}
+
+		err = model.engine.StreamBlueprintInstanceEvents(
+			context.TODO(),
+			instance.InstanceID,
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return DeployErrMsg{engine.SimplifyError(err, logger)}
+		}
+
+		return DeploymentStartedMsg{instanceID: instance.InstanceID}
+	}
+}
+
+func waitForNextEventCmd(model DeployModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		errStream := model.errStream
+		for {
+			select {
+			case event, open := <-model.eventStream:
+				if !open {
+					return DeployStreamClosedMsg{}
+				}
+				return DeployEventMsg{event: &event, receivedAt: time.Now()}
+			case err, open := <-errStream:
+				if !open {
+					// Receiving from a nil channel blocks so only
+					// the event stream is waited on from here.
+					errStream = nil
+				}
+				// A nil error does not end the stream,
+				// the next event is waited for instead.
+				if err != nil {
+					return DeployErrMsg{engine.SimplifyError(err, logger)}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package deployui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	elementKindStyle      = lipgloss.NewStyle().MarginLeft(2).Width(10).Foreground(lipgloss.Color("#4f46e5"))
+	elementNameStyle      = lipgloss.NewStyle().Width(40)
+	statusInProgressStyle = lipgloss.NewStyle().Width(26).Foreground(lipgloss.Color("#2563eb"))
+	statusSuccessStyle    = lipgloss.NewStyle().Width(26).Foreground(lipgloss.Color("#16a34a"))
+	statusFailureStyle    = lipgloss.NewStyle().Width(26).Foreground(lipgloss.Color("#dc2626"))
+	statusRolledBackStyle = lipgloss.NewStyle().Width(26).Foreground(lipgloss.Color("#f97316"))
+	elapsedStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	failureReasonStyle    = lipgloss.NewStyle().MarginLeft(4).Foreground(lipgloss.Color("#dc2626"))
+	finishedHeadingStyle  = lipgloss.NewStyle().MarginLeft(2).Bold(true)
+	deployErrorStyle      = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+	deployInfoLabelStyle  = lipgloss.NewStyle().MarginLeft(2)
+)
+
+// ChangesStagedMsg is dispatched when the change set to deploy
+// has been staged or retrieved from the deploy engine.
+type ChangesStagedMsg struct {
+	staged *stage.Result
+}
+
+// DeploymentStartedMsg is dispatched when the deployment has been started
+// and the blueprint instance event stream has been started.
+type DeploymentStartedMsg struct {
+	instanceID string
+}
+
+// DeployEventMsg is dispatched for each blueprint instance event
+// received from the deploy engine.
+type DeployEventMsg struct {
+	event      *types.BlueprintInstanceEvent
+	receivedAt time.Time
+}
+
+// DeployErrMsg is dispatched when an error occurs in staging changes,
+// starting the deployment or streaming deployment events.
+type DeployErrMsg struct {
+	err error
+}
+
+// DeployStreamClosedMsg is dispatched when the blueprint instance
+// event stream is closed by the deploy engine client.
+type DeployStreamClosedMsg struct{}
+
+// DeployModel is the model for deploying a blueprint instance
+// and rendering the live progress of each resource, child blueprint
+// and link in the deployment.
+type DeployModel struct {
+	spinner     spinner.Model
+	engine      engine.DeployEngine
+	opts        *deploy.Options
+	staged      *stage.Result
+	instanceID  string
+	eventStream chan types.BlueprintInstanceEvent
+	errStream   chan error
+	progress    *deploy.Progress
+	finished    bool
+	err         error
+	width       int
+	styles      *styles.CelerityStyles
+	logger      *zap.Logger
+}
+
+func (m DeployModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, stageChangesCmd(m, m.logger))
+}
+
+func (m DeployModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case ChangesStagedMsg:
+		m.staged = msg.staged
+		return m, startDeploymentCmd(m, m.logger)
+	case DeploymentStartedMsg:
+		m.instanceID = msg.instanceID
+		return m, waitForNextEventCmd(m, m.logger)
+	case DeployEventMsg:
+		m.progress.Apply(msg.event, msg.receivedAt)
+		if m.progress.Finished() {
+			m.finished = true
+			if err := m.progress.Err(); err != nil {
+				m.err = fmt.Errorf("deployment %w", err)
+			}
+			return m, tea.Quit
+		}
+		return m, waitForNextEventCmd(m, m.logger)
+	case DeployStreamClosedMsg:
+		if !m.finished {
+			m.err = fmt.Errorf(
+				"event stream for blueprint instance %q closed before the deployment finished",
+				m.instanceID,
+			)
+			return m, tea.Quit
+		}
+	case DeployErrMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m DeployModel) View() string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	if m.staged != nil {
+		sb.WriteString(deployInfoLabelStyle.Render("Change set: " + m.styles.Selected.Render(m.staged.ChangesetID)))
+		sb.WriteString("\n")
+	}
+	if m.instanceID != "" {
+		sb.WriteString(deployInfoLabelStyle.Render("Blueprint instance: " + m.styles.Selected.Render(m.instanceID)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(RenderProgress(m.progress, time.Now()))
+
+	if m.finished {
+		sb.WriteString("\n")
+		sb.WriteString(finishedHeadingStyle.Render("Deployment finished with status: "))
+		sb.WriteString(RenderInstanceStatus(m.progress))
+		sb.WriteString("\n")
+	}
+
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(deployErrorStyle.Render(m.err.Error()))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if !m.finished {
+		sb.WriteString(fmt.Sprintf("\n\n %s %s\n\n", m.spinner.View(), m.spinnerText()))
+	}
+
+	return sb.String()
+}
+
+func (m DeployModel) spinnerText() string {
+	if m.staged == nil {
+		return "Staging changes..."
+	}
+
+	if m.instanceID == "" {
+		return "Starting deployment..."
+	}
+
+	return "Deploying..."
+}
+
+// NewDeployModel creates a new model for deploying a blueprint instance.
+func NewDeployModel(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *deploy.Options,
+	celerityStyles *styles.CelerityStyles,
+) DeployModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return DeployModel{
+		spinner:     s,
+		engine:      engine,
+		opts:        opts,
+		styles:      celerityStyles,
+		logger:      logger,
+		progress:    deploy.NewProgress(),
+		eventStream: make(chan types.BlueprintInstanceEvent),
+		errStream:   make(chan error),
+	}
+}
+
+// RenderProgress renders a row for each resource, child blueprint and link
+// in a deployment or removal process with its status and elapsed time.
+// This is shared with other TUIs that stream blueprint instance events.
+func RenderProgress(progress *deploy.Progress, now time.Time) string {
+	sb := strings.Builder{}
+	for _, element := range progress.Elements() {
+		sb.WriteString(elementKindStyle.Render(string(element.Kind)))
+		sb.WriteString(elementNameStyle.Render(element.Name))
+		sb.WriteString(statusStyle(element.Category).Render(element.Status))
+		sb.WriteString(elapsedStyle.Render(element.Elapsed(now).Round(time.Second).String()))
+		sb.WriteString("\n")
+		for _, reason := range element.FailureReasons {
+			sb.WriteString(failureReasonStyle.Render("- " + reason))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// RenderInstanceStatus renders the latest known status of the blueprint instance
+// in a deployment or removal process.
+func RenderInstanceStatus(progress *deploy.Progress) string {
+	status := progress.InstanceStatus()
+	return statusStyle(deploy.InstanceStatusCategory(status)).
+		UnsetWidth().
+		Render(deploy.InstanceStatusName(status))
+}
+
+func statusStyle(category deploy.StatusCategory) lipgloss.Style {
+	switch category {
+	case deploy.StatusCategorySuccess:
+		return statusSuccessStyle
+	case deploy.StatusCategoryFailure:
+		return statusFailureStyle
+	case deploy.StatusCategoryRolledBack:
+		return statusRolledBackStyle
+	default:
+		return statusInProgressStyle
+	}
+}
@@ -0,0 +1,68 @@
+package deployui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	quitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+)
+
+type MainModel struct {
+	quitting bool
+	deploy   DeployModel
+	Error    error
+}
+
+func (m MainModel) Init() tea.Cmd {
+	return m.deploy.Init()
+}
+
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			// Quitting the TUI does not cancel a deployment that
+			// has already been started by the deploy engine.
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+
+	newDeploy, cmd := m.deploy.Update(msg)
+	deployModel, ok := newDeploy.(DeployModel)
+	if !ok {
+		panic("failed to perform assertion on deploy model")
+	}
+	m.deploy = deployModel
+	if deployModel.err != nil {
+		m.Error = deployModel.err
+	}
+	return m, cmd
+}
+
+func (m MainModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render(
+			"Stopped watching the deployment, it will continue to run in the deploy engine.",
+		)
+	}
+	return m.deploy.View()
+}
+
+func NewDeployApp(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *deploy.Options,
+	celerityStyles *styles.CelerityStyles,
+) (*MainModel, error) {
+	return &MainModel{
+		deploy: NewDeployModel(engine, logger, opts, celerityStyles),
+	}, nil
+}
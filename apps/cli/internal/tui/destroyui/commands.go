@@ -0,0 +1,80 @@
+package destroyui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/destroy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+func prepareCmd(model DestroyModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		target, err := destroy.Prepare(context.TODO(), model.engine, model.opts, logger)
+		if err != nil {
+			return DestroyErrMsg{err}
+		}
+
+		return TargetPreparedMsg{target: target}
+	}
+}
+
+func stageChangesCmd(model DestroyModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		staged, err := destroy.StageChanges(context.TODO(), model.engine, model.opts, logger)
+		if err != nil {
+			return DestroyErrMsg{err}
+		}
+
+		return ChangesStagedMsg{staged: staged}
+	}
+}
+
+func startDestroyCmd(model DestroyModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		instance, err := destroy.Start(context.TODO(), model.engine, model.opts, model.staged, logger)
+		if err != nil {
+			return DestroyErrMsg{err}
+		}
+
+		err = model.engine.StreamBlueprintInstanceEvents(
+			context.TODO(),
+			instance.InstanceID,
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return DestroyErrMsg{engine.SimplifyError(err, logger)}
+		}
+
+		return DestroyStartedMsg{instanceID: instance.InstanceID}
+	}
+}
+
+func waitForNextEventCmd(model DestroyModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		errStream := model.errStream
+		for {
+			select {
+			case event, open := <-model.eventStream:
+				if !open {
+					return DestroyStreamClosedMsg{}
+				}
+				return DestroyEventMsg{event: &event, receivedAt: time.Now()}
+			case err, open := <-errStream:
+				if !open {
+					// Receiving from a nil channel blocks so only
+					// the event stream is waited on from here.
+					errStream = nil
+				}
+				// A nil error does not end the stream,
+				// the next event is waited for instead.
+				if err != nil {
+					return DestroyErrMsg{engine.SimplifyError(err, logger)}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package destroyui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/deploy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/destroy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/deployui"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	protectedWarningStyle = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#f97316"))
+	finishedHeadingStyle  = lipgloss.NewStyle().MarginLeft(2).Bold(true)
+	destroyErrorStyle     = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+	destroyInfoLabelStyle = lipgloss.NewStyle().MarginLeft(2)
+)
+
+type destroyStage int
+
+const (
+	// Stage where the blueprint instance is resolved and
+	// checked against the list of protected instances.
+	destroyStagePrepare destroyStage = iota
+
+	// Stage where the user confirms the name of a protected
+	// blueprint instance before it is destroyed.
+	destroyStageConfirm
+
+	// Stage where changes are staged and the blueprint instance is destroyed.
+	destroyStageDestroy
+)
+
+// TargetPreparedMsg is dispatched when the blueprint instance to destroy
+// has been resolved and checked for deletion protection.
+type TargetPreparedMsg struct {
+	target *destroy.Target
+}
+
+// ChangesStagedMsg is dispatched when the changes for destroying
+// the blueprint instance have been staged.
+type ChangesStagedMsg struct {
+	staged *stage.Result
+}
+
+// DestroyStartedMsg is dispatched when the destroy process has been started
+// and the blueprint instance event stream has been started.
+type DestroyStartedMsg struct {
+	instanceID string
+}
+
+// DestroyEventMsg is dispatched for each blueprint instance event
+// received from the deploy engine.
+type DestroyEventMsg struct {
+	event      *types.BlueprintInstanceEvent
+	receivedAt time.Time
+}
+
+// DestroyErrMsg is dispatched when an error occurs in any stage
+// of destroying a blueprint instance.
+type DestroyErrMsg struct {
+	err error
+}
+
+// DestroyStreamClosedMsg is dispatched when the blueprint instance
+// event stream is closed by the deploy engine client.
+type DestroyStreamClosedMsg struct{}
+
+// DestroyModel is the model for destroying a blueprint instance
+// and rendering the live progress of each resource, child blueprint
+// and link as it is removed.
+type DestroyModel struct {
+	spinner      spinner.Model
+	confirmInput textinput.Model
+	engine       engine.DeployEngine
+	opts         *destroy.Options
+	stage        destroyStage
+	target       *destroy.Target
+	staged       *stage.Result
+	instanceID   string
+	eventStream  chan types.BlueprintInstanceEvent
+	errStream    chan error
+	progress     *deploy.Progress
+	finished     bool
+	err          error
+	styles       *styles.CelerityStyles
+	logger       *zap.Logger
+}
+
+func (m DestroyModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, prepareCmd(m, m.logger))
+}
+
+func (m DestroyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.stage != destroyStageConfirm {
+			return m, nil
+		}
+
+		if msg.Type == tea.KeyEnter {
+			if err := destroy.Confirm(m.target, m.confirmInput.Value()); err != nil {
+				m.err = err
+				return m, tea.Quit
+			}
+			m.stage = destroyStageDestroy
+			m.confirmInput.Blur()
+			return m, stageChangesCmd(m, m.logger)
+		}
+
+		var cmd tea.Cmd
+		m.confirmInput, cmd = m.confirmInput.Update(msg)
+		return m, cmd
+	case TargetPreparedMsg:
+		m.target = msg.target
+		if m.target.Protected {
+			m.stage = destroyStageConfirm
+			return m, m.confirmInput.Focus()
+		}
+		m.stage = destroyStageDestroy
+		return m, stageChangesCmd(m, m.logger)
+	case ChangesStagedMsg:
+		m.staged = msg.staged
+		return m, startDestroyCmd(m, m.logger)
+	case DestroyStartedMsg:
+		m.instanceID = msg.instanceID
+		return m, waitForNextEventCmd(m, m.logger)
+	case DestroyEventMsg:
+		m.progress.Apply(msg.event, msg.receivedAt)
+		if m.progress.Finished() {
+			m.finished = true
+			if err := m.progress.Err(); err != nil {
+				m.err = fmt.Errorf("destroy %w", err)
+			}
+			return m, tea.Quit
+		}
+		return m, waitForNextEventCmd(m, m.logger)
+	case DestroyStreamClosedMsg:
+		if !m.finished {
+			m.err = fmt.Errorf(
+				"event stream for blueprint instance %q closed before the destroy process finished",
+				m.instanceID,
+			)
+			return m, tea.Quit
+		}
+	case DestroyErrMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m DestroyModel) View() string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	if m.target != nil {
+		sb.WriteString(
+			destroyInfoLabelStyle.Render("Blueprint instance: " + m.styles.Selected.Render(m.target.InstanceName)),
+		)
+		sb.WriteString("\n")
+	}
+
+	if m.stage == destroyStageConfirm {
+		sb.WriteString("\n")
+		sb.WriteString(protectedWarningStyle.Render(
+			fmt.Sprintf(
+				"%q is a protected blueprint instance, enter the instance name to confirm that it should be destroyed.",
+				m.target.InstanceName,
+			),
+		))
+		sb.WriteString("\n\n  ")
+		sb.WriteString(m.confirmInput.View())
+		sb.WriteString("\n")
+	}
+
+	if m.staged != nil {
+		sb.WriteString(destroyInfoLabelStyle.Render("Change set: " + m.styles.Selected.Render(m.staged.ChangesetID)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(deployui.RenderProgress(m.progress, time.Now()))
+
+	if m.finished {
+		sb.WriteString("\n")
+		sb.WriteString(finishedHeadingStyle.Render("Destroy finished with status: "))
+		sb.WriteString(deployui.RenderInstanceStatus(m.progress))
+		sb.WriteString("\n")
+	}
+
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(destroyErrorStyle.Render(m.err.Error()))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if !m.finished && m.stage != destroyStageConfirm {
+		sb.WriteString(fmt.Sprintf("\n\n %s %s\n\n", m.spinner.View(), m.spinnerText()))
+	}
+
+	return sb.String()
+}
+
+func (m DestroyModel) spinnerText() string {
+	if m.stage == destroyStagePrepare {
+		return "Checking blueprint instance..."
+	}
+
+	if m.staged == nil {
+		return "Staging changes..."
+	}
+
+	if m.instanceID == "" {
+		return "Starting destroy..."
+	}
+
+	return "Destroying..."
+}
+
+// NewDestroyModel creates a new model for destroying a blueprint instance.
+func NewDestroyModel(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *destroy.Options,
+	celerityStyles *styles.CelerityStyles,
+) DestroyModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	confirmInput := textinput.New()
+	confirmInput.Placeholder = "instance name"
+	confirmInput.PromptStyle = celerityStyles.Selectable
+	confirmInput.TextStyle = celerityStyles.Selected
+
+	return DestroyModel{
+		spinner:      s,
+		confirmInput: confirmInput,
+		engine:       engine,
+		opts:         opts,
+		stage:        destroyStagePrepare,
+		styles:       celerityStyles,
+		logger:       logger,
+		progress:     deploy.NewProgress(),
+		eventStream:  make(chan types.BlueprintInstanceEvent),
+		errStream:    make(chan error),
+	}
+}
@@ -0,0 +1,71 @@
+package destroyui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/destroy"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	quitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+)
+
+type MainModel struct {
+	quitting bool
+	destroy  DestroyModel
+	Error    error
+}
+
+func (m MainModel) Init() tea.Cmd {
+	return m.destroy.Init()
+}
+
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			// Quitting the TUI does not cancel a destroy process that
+			// has already been started by the deploy engine.
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+
+	newDestroy, cmd := m.destroy.Update(msg)
+	destroyModel, ok := newDestroy.(DestroyModel)
+	if !ok {
+		panic("failed to perform assertion on destroy model")
+	}
+	m.destroy = destroyModel
+	if destroyModel.err != nil {
+		m.Error = destroyModel.err
+	}
+	return m, cmd
+}
+
+func (m MainModel) View() string {
+	if m.quitting && m.destroy.instanceID == "" {
+		return quitTextStyle.Render("Had enough? See you next time.")
+	}
+	if m.quitting {
+		return quitTextStyle.Render(
+			"Stopped watching the destroy process, it will continue to run in the deploy engine.",
+		)
+	}
+	return m.destroy.View()
+}
+
+func NewDestroyApp(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *destroy.Options,
+	celerityStyles *styles.CelerityStyles,
+) (*MainModel, error) {
+	return &MainModel{
+		destroy: NewDestroyModel(engine, logger, opts, celerityStyles),
+	}, nil
+}
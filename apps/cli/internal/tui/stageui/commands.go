@@ -0,0 +1,62 @@
+package stageui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"go.uber.org/zap"
+)
+
+func startStageStreamCmd(model StageModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		payload, err := stage.CreateChangesetPayload(model.opts)
+		if err != nil {
+			return StageErrMsg{err}
+		}
+
+		changeset, err := model.engine.CreateChangeset(context.TODO(), payload)
+		if err != nil {
+			return StageErrMsg{engine.SimplifyError(err, logger)}
+		}
+
+		err = model.engine.StreamChangeStagingEvents(
+			context.TODO(),
+			changeset.ID,
+			model.eventStream,
+			model.errStream,
+		)
+		if err != nil {
+			return StageErrMsg{engine.SimplifyError(err, logger)}
+		}
+
+		return ChangesetCreatedMsg{changesetID: changeset.ID}
+	}
+}
+
+func waitForNextEventCmd(model StageModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		errStream := model.errStream
+		for {
+			select {
+			case event, open := <-model.eventStream:
+				if !open {
+					return StageStreamClosedMsg{}
+				}
+				return StageEventMsg(&event)
+			case err, open := <-errStream:
+				if !open {
+					// Receiving from a nil channel blocks so only
+					// the event stream is waited on from here.
+					errStream = nil
+				}
+				// A nil error does not end the stream,
+				// the next event is waited for instead.
+				if err != nil {
+					return StageErrMsg{engine.SimplifyError(err, logger)}
+				}
+			}
+		}
+	}
+}
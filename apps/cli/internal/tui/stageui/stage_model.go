@@ -0,0 +1,228 @@
+package stageui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	elementKindStyle    = lipgloss.NewStyle().MarginLeft(2).Width(10).Foreground(lipgloss.Color("#4f46e5"))
+	elementNameStyle    = lipgloss.NewStyle().Width(40)
+	actionCreateStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#16a34a"))
+	actionUpdateStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#2563eb"))
+	actionRecreateStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f97316"))
+	actionRemoveStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626"))
+	actionNoChangeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	summaryHeadingStyle = lipgloss.NewStyle().MarginLeft(2).Bold(true)
+	summaryStyle        = lipgloss.NewStyle().MarginLeft(2)
+	stageErrorStyle     = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+)
+
+// ChangesetCreatedMsg is dispatched when a change set has been created
+// and the change staging event stream has been started.
+type ChangesetCreatedMsg struct {
+	changesetID string
+}
+
+// StageEventMsg is dispatched for each change staging event
+// received from the deploy engine.
+type StageEventMsg *types.ChangeStagingEvent
+
+// StageErrMsg is dispatched when an error occurs in creating
+// the change set or streaming change staging events.
+type StageErrMsg struct {
+	err error
+}
+
+// StageStreamClosedMsg is dispatched when the change staging
+// event stream is closed by the deploy engine client.
+type StageStreamClosedMsg struct{}
+
+type stagedElement struct {
+	kind   string
+	name   string
+	action stage.ChangeAction
+}
+
+// StageModel is the model for streaming and rendering
+// the changes staged for a blueprint instance.
+type StageModel struct {
+	spinner     spinner.Model
+	engine      engine.DeployEngine
+	opts        *stage.Options
+	changesetID string
+	eventStream chan types.ChangeStagingEvent
+	errStream   chan error
+	elements    []*stagedElement
+	summary     *stage.Summary
+	finished    bool
+	err         error
+	width       int
+	styles      *styles.CelerityStyles
+	logger      *zap.Logger
+}
+
+func (m StageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, startStageStreamCmd(m, m.logger))
+}
+
+func (m StageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case ChangesetCreatedMsg:
+		m.changesetID = msg.changesetID
+		return m, waitForNextEventCmd(m, m.logger)
+	case StageEventMsg:
+		if completeChanges, isComplete := (*types.ChangeStagingEvent)(msg).AsCompleteChanges(); isComplete {
+			m.summary = stage.SummariseChanges(completeChanges.Changes)
+			m.finished = true
+			return m, tea.Quit
+		}
+		element := stagedElementFromEvent(msg)
+		if element != nil {
+			m.elements = append(m.elements, element)
+		}
+		return m, waitForNextEventCmd(m, m.logger)
+	case StageStreamClosedMsg:
+		if !m.finished {
+			m.err = fmt.Errorf(
+				"change staging stream for change set %q closed before staging was complete",
+				m.changesetID,
+			)
+			return m, tea.Quit
+		}
+	case StageErrMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m StageModel) View() string {
+	sb := strings.Builder{}
+	if m.changesetID != "" {
+		sb.WriteString("\n  Change set: " + m.styles.Selected.Render(m.changesetID) + "\n\n")
+	}
+
+	for _, element := range m.elements {
+		sb.WriteString(elementKindStyle.Render(element.kind))
+		sb.WriteString(elementNameStyle.Render(element.name))
+		sb.WriteString(renderAction(element.action))
+		sb.WriteString("\n")
+	}
+
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(stageErrorStyle.Render(m.err.Error()))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if !m.finished {
+		sb.WriteString(fmt.Sprintf("\n\n %s Staging changes...\n\n", m.spinner.View()))
+		return sb.String()
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(summaryHeadingStyle.Render("Change staging complete"))
+	sb.WriteString("\n")
+	sb.WriteString(summaryStyle.Render(renderSummary(m.summary)))
+	sb.WriteString("\n\n")
+	return sb.String()
+}
+
+// NewStageModel creates a new model for staging changes
+// for a blueprint instance.
+func NewStageModel(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *stage.Options,
+	celerityStyles *styles.CelerityStyles,
+) StageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return StageModel{
+		spinner:     s,
+		engine:      engine,
+		opts:        opts,
+		styles:      celerityStyles,
+		logger:      logger,
+		eventStream: make(chan types.ChangeStagingEvent),
+		errStream:   make(chan error),
+	}
+}
+
+func stagedElementFromEvent(event *types.ChangeStagingEvent) *stagedElement {
+	switch event.GetType() {
+	case types.ChangeStagingEventTypeResourceChanges:
+		resourceChanges, _ := event.AsResourceChanges()
+		return &stagedElement{
+			kind: "resource",
+			name: resourceChanges.ResourceName,
+			action: stage.ResourceChangeAction(
+				&resourceChanges.Changes,
+				resourceChanges.New,
+				resourceChanges.Removed,
+			),
+		}
+	case types.ChangeStagingEventTypeChildChanges:
+		childChanges, _ := event.AsChildChanges()
+		return &stagedElement{
+			kind:   "child",
+			name:   childChanges.ChildBlueprintName,
+			action: stage.ChildChangeAction(childChanges),
+		}
+	case types.ChangeStagingEventTypeLinkChanges:
+		linkChanges, _ := event.AsLinkChanges()
+		return &stagedElement{
+			kind:   "link",
+			name:   stage.LinkName(linkChanges.ResourceAName, linkChanges.ResourceBName),
+			action: stage.LinkChangeAction(linkChanges),
+		}
+	default:
+		return nil
+	}
+}
+
+func renderAction(action stage.ChangeAction) string {
+	switch action {
+	case stage.ChangeActionCreate:
+		return actionCreateStyle.Render(string(action))
+	case stage.ChangeActionUpdate:
+		return actionUpdateStyle.Render(string(action))
+	case stage.ChangeActionRecreate:
+		return actionRecreateStyle.Render(string(action))
+	case stage.ChangeActionRemove:
+		return actionRemoveStyle.Render(string(action))
+	default:
+		return actionNoChangeStyle.Render(string(action))
+	}
+}
+
+func renderSummary(summary *stage.Summary) string {
+	return fmt.Sprintf(
+		"%s to create, %s to update, %s to recreate, %s to remove",
+		actionCreateStyle.Render(fmt.Sprintf("%d", summary.Creates)),
+		actionUpdateStyle.Render(fmt.Sprintf("%d", summary.Updates)),
+		actionRecreateStyle.Render(fmt.Sprintf("%d", summary.Recreates)),
+		actionRemoveStyle.Render(fmt.Sprintf("%d", summary.Removals)),
+	)
+}
@@ -0,0 +1,64 @@
+package stageui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/stage"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"go.uber.org/zap"
+)
+
+var (
+	quitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+)
+
+type MainModel struct {
+	quitting bool
+	stage    StageModel
+	Error    error
+}
+
+func (m MainModel) Init() tea.Cmd {
+	return m.stage.Init()
+}
+
+func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+
+	newStage, cmd := m.stage.Update(msg)
+	stageModel, ok := newStage.(StageModel)
+	if !ok {
+		panic("failed to perform assertion on stage model")
+	}
+	m.stage = stageModel
+	if stageModel.err != nil {
+		m.Error = stageModel.err
+	}
+	return m, cmd
+}
+
+func (m MainModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render("Had enough? See you next time.")
+	}
+	return m.stage.View()
+}
+
+func NewStageApp(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *stage.Options,
+	celerityStyles *styles.CelerityStyles,
+) (*MainModel, error) {
+	return &MainModel{
+		stage: NewStageModel(engine, logger, opts, celerityStyles),
+	}, nil
+}
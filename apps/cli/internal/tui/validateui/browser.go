@@ -0,0 +1,389 @@
+package validateui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
+)
+
+const (
+	// detailHeight is the number of lines reserved for the details
+	// of the focused diagnostic below the diagnostics browser
+	// so the browser does not move as the focus changes,
+	// details that do not fit can be scrolled.
+	detailHeight = 12
+	// minBrowserHeight is the minimum height of the diagnostics browser
+	// for small terminal windows.
+	minBrowserHeight = 5
+)
+
+var (
+	hiddenLevelStyle    = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#6b7280")).Strikethrough(true)
+	diagnosticItemStyle = lipgloss.NewStyle().PaddingLeft(2)
+	detailStyle         = lipgloss.NewStyle().MarginLeft(2)
+)
+
+// diagnosticItem is an item in the diagnostics browser.
+type diagnosticItem struct {
+	diagnostic    *bpcore.Diagnostic
+	blueprintFile string
+	filterText    string
+}
+
+func (i diagnosticItem) FilterValue() string {
+	return i.filterText
+}
+
+func (i diagnosticItem) location() string {
+	if !validate.HasPreciseRange(i.diagnostic.Range) {
+		return i.blueprintFile
+	}
+
+	return fmt.Sprintf(
+		"%s:%d:%d",
+		i.blueprintFile,
+		i.diagnostic.Range.Start.Line,
+		i.diagnostic.Range.Start.Column,
+	)
+}
+
+func newDiagnosticItem(diagnostic *bpcore.Diagnostic, blueprintFile string) diagnosticItem {
+	i := diagnosticItem{
+		diagnostic:    diagnostic,
+		blueprintFile: blueprintFile,
+	}
+	i.filterText = fmt.Sprintf(
+		"%s %s %s",
+		validate.DiagnosticLevelName(diagnostic.Level),
+		diagnostic.Message,
+		i.location(),
+	)
+	return i
+}
+
+// diagnosticItemDelegate renders each diagnostic on a single line
+// so as many diagnostics as possible fit in the browser.
+type diagnosticItemDelegate struct{}
+
+func (d diagnosticItemDelegate) Height() int {
+	return 1
+}
+
+func (d diagnosticItemDelegate) Spacing() int {
+	return 0
+}
+
+func (d diagnosticItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d diagnosticItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(diagnosticItem)
+	if !ok {
+		return
+	}
+
+	prefix := "  "
+	messageStyle := lipgloss.NewStyle()
+	if index == m.Index() {
+		prefix = "> "
+		messageStyle = messageStyle.Foreground(lipgloss.Color("#4f46e5"))
+	}
+
+	// Messages can span multiple lines, only the first line
+	// is shown in the browser.
+	message, _, _ := strings.Cut(i.diagnostic.Message, "\n")
+	line := prefix +
+		levelStyle(i.diagnostic.Level).UnsetMarginLeft().Render(
+			fmt.Sprintf("%-7s", validate.DiagnosticLevelName(i.diagnostic.Level)),
+		) + " " +
+		messageStyle.Render(message) + " " +
+		locationStyle.UnsetMarginLeft().Render(i.location())
+
+	fmt.Fprint(w, diagnosticItemStyle.MaxWidth(m.Width()).Render(line))
+}
+
+func levelStyle(level bpcore.DiagnosticLevel) lipgloss.Style {
+	switch level {
+	case bpcore.DiagnosticLevelError:
+		return diagnosticLevelErrorStyle
+	case bpcore.DiagnosticLevelWarning:
+		return diagnosticLevelWarnStyle
+	default:
+		return diagnosticLevelInfoStyle
+	}
+}
+
+type browserKeyMap struct {
+	ToggleErrors   key.Binding
+	ToggleWarnings key.Binding
+	ToggleInfo     key.Binding
+	Open           key.Binding
+	Rerun          key.Binding
+	DetailDown     key.Binding
+	DetailUp       key.Binding
+}
+
+func newBrowserKeyMap() *browserKeyMap {
+	return &browserKeyMap{
+		ToggleErrors: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "toggle errors"),
+		),
+		ToggleWarnings: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "toggle warnings"),
+		),
+		ToggleInfo: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "toggle info"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("o", "enter"),
+			key.WithHelp("o", "open in $EDITOR"),
+		),
+		Rerun: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "re-run validation"),
+		),
+		DetailDown: key.NewBinding(
+			key.WithKeys("J", "shift+down"),
+			key.WithHelp("J", "scroll details down"),
+		),
+		DetailUp: key.NewBinding(
+			key.WithKeys("K", "shift+up"),
+			key.WithHelp("K", "scroll details up"),
+		),
+	}
+}
+
+func (k *browserKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Open, k.Rerun}
+}
+
+func (k *browserKeyMap) FullHelp() []key.Binding {
+	return []key.Binding{
+		k.ToggleErrors,
+		k.ToggleWarnings,
+		k.ToggleInfo,
+		k.Open,
+		k.Rerun,
+		k.DetailDown,
+		k.DetailUp,
+	}
+}
+
+func newDiagnosticsBrowser(keys *browserKeyMap) list.Model {
+	browser := list.New([]list.Item{}, diagnosticItemDelegate{}, 0, 0)
+	browser.SetShowTitle(false)
+	browser.SetStatusBarItemName("diagnostic", "diagnostics")
+	browser.AdditionalShortHelpKeys = keys.ShortHelp
+	browser.AdditionalFullHelpKeys = keys.FullHelp
+	// Quitting is handled by the main model so the final view
+	// is rendered in the same way for all the ways to exit.
+	browser.DisableQuitKeybindings()
+	return browser
+}
+
+// newDetailPane creates the pane for the details of the focused
+// diagnostic, the last line of the reserved space is kept
+// for the scroll indicator.
+func newDetailPane() viewport.Model {
+	pane := viewport.New(0, detailHeight-1)
+	// Scrolling is handled by the browser key map as the default
+	// keys are used to move through the diagnostics.
+	pane.KeyMap = viewport.KeyMap{}
+	return pane
+}
+
+// allItems returns the diagnostics for all the blueprint files that have
+// been validated, blueprint files that could not be validated are included
+// as an error so they can be found in the browser.
+func (m ValidateModel) allItems() []diagnosticItem {
+	items := []diagnosticItem{}
+	if !m.multipleFiles() {
+		for _, event := range m.collected {
+			items = append(items, newDiagnosticItem(&event.Diagnostic, m.blueprintFile))
+		}
+		return items
+	}
+
+	for _, result := range m.fileResults {
+		if result == nil {
+			continue
+		}
+		for _, diagnostic := range result.Diagnostics {
+			items = append(items, newDiagnosticItem(diagnostic, result.BlueprintFile))
+		}
+		if result.Err != nil {
+			items = append(items, newDiagnosticItem(
+				&bpcore.Diagnostic{
+					Level:   bpcore.DiagnosticLevelError,
+					Message: fmt.Sprintf("validation could not be carried out: %s", result.Err),
+				},
+				result.BlueprintFile,
+			))
+		}
+	}
+	return items
+}
+
+// visibleItems returns the items for the diagnostic levels
+// that have not been hidden by the user.
+func (m ValidateModel) visibleItems() []list.Item {
+	items := []list.Item{}
+	for _, i := range m.allItems() {
+		if !m.hiddenLevels[i.diagnostic.Level] {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+func (m ValidateModel) toggleLevel(level bpcore.DiagnosticLevel) (ValidateModel, tea.Cmd) {
+	hiddenLevels := map[bpcore.DiagnosticLevel]bool{}
+	for hiddenLevel, hidden := range m.hiddenLevels {
+		hiddenLevels[hiddenLevel] = hidden
+	}
+	hiddenLevels[level] = !hiddenLevels[level]
+	m.hiddenLevels = hiddenLevels
+	return m, m.list.SetItems(m.visibleItems())
+}
+
+// headerView renders the number of diagnostics for each level, levels that
+// have been hidden from the browser are crossed out.
+func (m ValidateModel) headerView() string {
+	counts := validate.DiagnosticCounts{}
+	for _, i := range m.allItems() {
+		counts.Add(i.diagnostic.Level)
+	}
+
+	levels := []struct {
+		level bpcore.DiagnosticLevel
+		key   string
+		text  string
+	}{
+		{bpcore.DiagnosticLevelError, "e", fmt.Sprintf("%d errors", counts.Errors)},
+		{bpcore.DiagnosticLevelWarning, "w", fmt.Sprintf("%d warnings", counts.Warnings)},
+		{bpcore.DiagnosticLevelInfo, "i", fmt.Sprintf("%d info", counts.Info)},
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(validateCategroyStyle.MarginLeft(2).Render("Diagnostics"))
+	for _, level := range levels {
+		text := fmt.Sprintf("[%s] %s", level.key, level.text)
+		if m.hiddenLevels[level.level] {
+			sb.WriteString(hiddenLevelStyle.Render(text))
+		} else {
+			sb.WriteString(levelStyle(level.level).Render(text))
+		}
+	}
+
+	if !m.finished {
+		sb.WriteString(fmt.Sprintf("  %s %s", m.spinner.View(), m.progressText()))
+	}
+	return sb.String()
+}
+
+func (m ValidateModel) progressText() string {
+	if !m.multipleFiles() {
+		return "Validating project..."
+	}
+
+	completed := 0
+	for _, result := range m.fileResults {
+		if result != nil {
+			completed += 1
+		}
+	}
+	return fmt.Sprintf("Validated %d of %d blueprint files...", completed, len(m.fileResults))
+}
+
+// selectedDiagnostic returns the focused diagnostic in the browser,
+// nil is returned when there are no diagnostics to browse.
+func (m ValidateModel) selectedDiagnostic() *bpcore.Diagnostic {
+	i, ok := m.list.SelectedItem().(diagnosticItem)
+	if !ok {
+		return nil
+	}
+	return i.diagnostic
+}
+
+// updateDetail renders the details of the focused diagnostic
+// in the detail pane, the pane is scrolled back to the top
+// when the focus moves to a different diagnostic.
+func (m ValidateModel) updateDetail(previous *bpcore.Diagnostic) ValidateModel {
+	m.detail.SetContent(m.detailContent())
+	if m.selectedDiagnostic() != previous {
+		m.detail.GotoTop()
+	}
+	return m
+}
+
+// detailContent renders the full message of the focused diagnostic
+// followed by a snippet of the lines of the blueprint file
+// that it refers to.
+func (m ValidateModel) detailContent() string {
+	i, ok := m.list.SelectedItem().(diagnosticItem)
+	if !ok {
+		return ""
+	}
+
+	message := lipgloss.NewStyle().Width(m.detail.Width).Render(i.diagnostic.Message)
+	source := m.sources[i.blueprintFile]
+	snippet := ""
+	if source != nil {
+		snippet = source.Render(
+			i.diagnostic.Range,
+			m.opts.Hyperlinks,
+			snippetStyleForLevel(i.diagnostic.Level),
+		)
+	}
+	if snippet == "" {
+		snippet = locationStyle.UnsetMarginLeft().Render(i.location())
+	}
+	return message + "\n" + snippet
+}
+
+// detailView renders the detail pane followed by a scroll indicator
+// when the details of the focused diagnostic do not fit in the pane.
+func (m ValidateModel) detailView() string {
+	indicator := ""
+	if !m.detail.AtTop() || !m.detail.AtBottom() {
+		indicator = locationStyle.UnsetMarginLeft().Render(
+			fmt.Sprintf("J/K to scroll details (%3.f%%)", m.detail.ScrollPercent()*100),
+		)
+	}
+	return detailStyle.Render(m.detail.View() + "\n" + indicator)
+}
+
+// resizeBrowser gives the diagnostics browser the space in the terminal
+// window that is not taken up by the rest of the view.
+func (m ValidateModel) resizeBrowser() ValidateModel {
+	if m.height == 0 {
+		return m
+	}
+
+	// The selected blueprint is rendered above the validate view.
+	reserved := 2
+	reserved += lipgloss.Height(m.headerView()) + detailHeight + 2
+	if len(m.changed) > 0 {
+		reserved += 1
+	}
+	if m.finished {
+		reserved += lipgloss.Height(m.summaryView())
+	}
+	reserved += lipgloss.Height(m.watchingView())
+
+	m.list.SetSize(m.width, max(m.height-reserved, minBrowserHeight))
+	m.detail.Width = max(m.width-4, 20)
+	return m
+}
@@ -1,12 +1,14 @@
 package validateui
 
 import (
-	"context"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/watch"
 	"go.uber.org/zap"
 )
 
@@ -14,9 +16,10 @@ var (
 	True = true
 )
 
-func selectBlueprintCmd(blueprintFile string) tea.Cmd {
+func selectBlueprintCmd(source string, blueprintFile string) tea.Cmd {
 	return func() tea.Msg {
 		return SelectBlueprintMsg{
+			source:        source,
 			blueprintFile: blueprintFile,
 		}
 	}
@@ -30,39 +33,146 @@ func clearSelectedBlueprintCmd() tea.Cmd {
 
 func startValidateStreamCmd(model ValidateModel, logger *zap.Logger) tea.Cmd {
 	return func() tea.Msg {
+		if err := prepareRun(&model, logger); err != nil {
+			return ValidateErrMsg{run: model.run, err: err}
+		}
+
+		docInfo, err := resolveDocumentInfo(model)
+		if err != nil {
+			return ValidateErrMsg{run: model.run, err: err}
+		}
+
 		blueprintValidation, err := model.engine.CreateBlueprintValidation(
-			context.TODO(),
+			model.ctx,
 			&types.CreateBlueprintValidationPayload{
-				BlueprintDocumentInfo: types.BlueprintDocumentInfo{
-					FileSourceScheme: "file",
-					Directory:        "/",
-					BlueprintFile:    model.blueprintFile,
-				},
+				BlueprintDocumentInfo: docInfo,
+				Config:                model.deployConfig,
+			},
+			&types.CreateBlueprintValidationQuery{
+				CheckPluginConfig: model.checkPluginConfig,
 			},
-			&types.CreateBlueprintValidationQuery{},
 		)
 		if err != nil {
 			time.Sleep(10 * time.Second)
-			return ValidateErrMsg{engine.SimplifyError(err, logger)}
+			return ValidateErrMsg{run: model.run, err: engine.SimplifyError(err, logger)}
 		}
 
 		err = model.engine.StreamBlueprintValidationEvents(
-			context.TODO(),
+			model.ctx,
 			blueprintValidation.ID,
 			model.resultStream,
 			model.errStream,
 		)
 		if err != nil {
-			return ValidateErrMsg{err}
+			return ValidateErrMsg{run: model.run, err: err}
+		}
+		return nil
+	}
+}
+
+// prepareRun updates the set of watched files in watch mode and reloads
+// the deploy config when the blueprint is validated again, either on a change
+// or when requested from the diagnostics browser, child blueprints
+// may have been added or removed since the last run.
+func prepareRun(model *ValidateModel, logger *zap.Logger) error {
+	if model.watcher != nil {
+		// The blueprint file may have been selected in the TUI,
+		// so it can differ from the blueprint file in the options.
+		watchOpts := *model.opts
+		watchOpts.BlueprintFiles = []string{model.blueprintFile}
+		files := watchOpts.WatchFiles
+		if !blueprint.IsRemoteSource(model.source) {
+			var err error
+			files, err = validate.WatchedFiles(&watchOpts)
+			if err != nil {
+				logger.Debug("failed to resolve child blueprints to watch", zap.Error(err))
+			}
 		}
+		if err := model.watcher.SetFiles(files); err != nil {
+			return err
+		}
+	}
+
+	if model.run == 0 || model.opts.LoadDeployConfig == nil {
 		return nil
 	}
+
+	deployConfig, err := model.opts.LoadDeployConfig()
+	if err != nil {
+		return err
+	}
+	model.deployConfig = deployConfig
+	return nil
 }
 
 func waitForNextResultCmd(model ValidateModel) tea.Cmd {
 	return func() tea.Msg {
-		event := <-model.resultStream
-		return ValidateResultMsg(&event)
+		select {
+		case event, open := <-model.resultStream:
+			if !open {
+				return ValidateResultMsg{run: model.run}
+			}
+			return ValidateResultMsg{run: model.run, event: &event}
+		case <-model.ctx.Done():
+			// The message is ignored as validation has been started again.
+			return ValidateResultMsg{run: model.run}
+		}
+	}
+}
+
+// startValidateFilesCmd validates multiple blueprint files concurrently,
+// the result for each file is sent to the file result stream
+// once the file has been validated.
+func startValidateFilesCmd(model ValidateModel, logger *zap.Logger) tea.Cmd {
+	return func() tea.Msg {
+		opts := *model.opts
+		if model.run > 0 && opts.LoadDeployConfig != nil {
+			// The deploy config may have changed since the last run.
+			deployConfig, err := opts.LoadDeployConfig()
+			if err != nil {
+				close(model.fileResultStream)
+				return ValidateErrMsg{run: model.run, err: err}
+			}
+			opts.DeployConfig = deployConfig
+		}
+
+		validate.ValidateFiles(
+			model.ctx,
+			model.engine,
+			&opts,
+			logger,
+			func(result *validate.FileResult) {
+				model.fileResultStream <- result
+			},
+		)
+		close(model.fileResultStream)
+		return nil
+	}
+}
+
+func waitForFileResultCmd(model ValidateModel) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case result, open := <-model.fileResultStream:
+			if !open {
+				return FileResultMsg{run: model.run}
+			}
+			return FileResultMsg{run: model.run, result: result}
+		case <-model.ctx.Done():
+			// The message is ignored as validation has been started again.
+			return FileResultMsg{run: model.run}
+		}
+	}
+}
+
+func waitForChangeCmd(watcher *watch.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case changed := <-watcher.Changes():
+			return WatchChangeMsg{changed}
+		case err := <-watcher.Errors():
+			return WatchErrMsg{err}
+		}
 	}
 }
 
@@ -75,6 +185,14 @@ func checkForErrCmd(model ValidateModel) tea.Cmd {
 		case newErr := <-model.errStream:
 			err = newErr
 		}
-		return ValidateErrMsg{err}
+		return ValidateErrMsg{run: model.run, err: err}
 	}
 }
+
+func resolveDocumentInfo(model ValidateModel) (types.BlueprintDocumentInfo, error) {
+	if blueprint.IsRemoteSource(model.source) {
+		return blueprint.RemoteDocumentInfo(model.source, model.blueprintFile)
+	}
+
+	return blueprint.ResolveDocumentInfo(model.blueprintFile, model.remoteEngine)
+}
@@ -0,0 +1,51 @@
+package validateui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errNoEditor = errors.New("set the EDITOR environment variable to open diagnostics in an editor")
+)
+
+// editorFinishedMsg is sent when the editor opened
+// from the diagnostics browser exits.
+type editorFinishedMsg struct {
+	err error
+}
+
+// editorCommand creates the command to open a file at a line and column in
+// the editor set in $EDITOR, falling back to $VISUAL.
+// The editor command can include arguments (e.g. "code --wait"),
+// editors that do not accept the "+line" argument understood by most
+// terminal editors are opened with their own syntax for a location.
+func editorCommand(file string, line int, column int) (*exec.Cmd, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+
+	parts := strings.Fields(editor)
+	if len(parts) == 0 {
+		return nil, errNoEditor
+	}
+
+	name := strings.TrimSuffix(filepath.Base(parts[0]), ".exe")
+	args := parts[1:]
+	location := fmt.Sprintf("%s:%d:%d", file, line, column)
+	switch name {
+	case "code", "code-insiders", "codium", "cursor":
+		args = append(args, "--goto", location)
+	case "subl", "zed", "hx":
+		args = append(args, location)
+	default:
+		args = append(args, fmt.Sprintf("+%d", line), file)
+	}
+
+	return exec.Command(parts[0], args...), nil
+}
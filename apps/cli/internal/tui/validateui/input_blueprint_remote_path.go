@@ -1 +1,126 @@
 package validateui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+)
+
+var (
+	remotePathErrorStyle = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#dc2626"))
+)
+
+// backToSelectSourceMsg is sent when the user leaves the remote
+// location input to select a different blueprint source.
+type backToSelectSourceMsg struct{}
+
+// InputBlueprintRemotePathModel is the model for entering the location
+// of a blueprint document in a remote source such as an object storage
+// bucket or a public HTTPS URL.
+type InputBlueprintRemotePathModel struct {
+	input  textinput.Model
+	source string
+	styles *styles.CelerityStyles
+	err    error
+}
+
+func (m InputBlueprintRemotePathModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m InputBlueprintRemotePathModel) Update(msg tea.Msg) (InputBlueprintRemotePathModel, tea.Cmd) {
+	if keyMsg, isKeyMsg := msg.(tea.KeyMsg); isKeyMsg && keyMsg.Type == tea.KeyEsc {
+		m.err = nil
+		m.input.Blur()
+		return m, func() tea.Msg {
+			return backToSelectSourceMsg{}
+		}
+	}
+
+	if keyMsg, isKeyMsg := msg.(tea.KeyMsg); isKeyMsg && keyMsg.Type == tea.KeyEnter {
+		location := strings.TrimSpace(m.input.Value())
+		_, err := blueprint.RemoteDocumentInfo(m.source, location)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+
+		m.err = nil
+		m.input.Blur()
+		return m, selectBlueprintCmd(m.source, location)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m InputBlueprintRemotePathModel) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(titleStyle.Render(remoteLocationPrompt(m.source)))
+	sb.WriteString("\n\n  ")
+	sb.WriteString(m.input.View())
+	sb.WriteString("\n")
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(remotePathErrorStyle.Render(m.err.Error()))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("enter validate • esc back"))
+	return sb.String()
+}
+
+// Focus prepares the input for the provided remote source
+// and focuses it so the user can start typing.
+func (m *InputBlueprintRemotePathModel) Focus(source string) tea.Cmd {
+	m.source = source
+	m.err = nil
+	m.input.SetValue("")
+	m.input.Placeholder = remoteLocationPlaceholder(source)
+	return m.input.Focus()
+}
+
+// NewInputBlueprintRemotePath creates a new model for entering
+// the location of a blueprint document in a remote source.
+func NewInputBlueprintRemotePath(celerityStyles *styles.CelerityStyles) InputBlueprintRemotePathModel {
+	input := textinput.New()
+	input.PromptStyle = celerityStyles.Selectable
+	input.TextStyle = celerityStyles.Selected
+	input.CharLimit = 1024
+	input.Width = 80
+
+	return InputBlueprintRemotePathModel{
+		input:  input,
+		styles: celerityStyles,
+	}
+}
+
+func remoteLocationPrompt(source string) string {
+	switch source {
+	case consts.BlueprintSourceS3:
+		return "Enter the S3 bucket and path of the blueprint file:"
+	case consts.BlueprintSourceGCS:
+		return "Enter the Google Cloud Storage bucket and path of the blueprint file:"
+	case consts.BlueprintSourceAzureBlob:
+		return "Enter the Azure Blob Storage container and path of the blueprint file:"
+	default:
+		return "Enter the URL of the blueprint file:"
+	}
+}
+
+func remoteLocationPlaceholder(source string) string {
+	switch source {
+	case consts.BlueprintSourceS3, consts.BlueprintSourceGCS:
+		return "bucket-name/path/to/app.blueprint.yaml"
+	case consts.BlueprintSourceAzureBlob:
+		return "container-name/path/to/app.blueprint.yaml"
+	default:
+		return "https://example.com/path/to/app.blueprint.yaml"
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/consts"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
 )
 
 type SelectBlueprintMsg struct {
+	source        string
 	blueprintFile string
 }
 
@@ -25,6 +27,7 @@ type SelectBlueprintModel struct {
 	filepicker   filepicker.Model
 	styles       styles.CelerityStyles
 	sourceList   list.Model
+	remotePath   InputBlueprintRemotePathModel
 	source       string
 	selectedFile string
 	autoValidate bool
@@ -47,7 +50,7 @@ const (
 
 	// Stage where the user inputs the location of the file
 	// relative to a remote source scheme.
-	// selectBlueprintStageInputFileLocation
+	selectBlueprintStageInputFileLocation
 
 	// Stage where the user selects a local file.
 	selectBlueprintStageSelectLocalFile
@@ -75,7 +78,7 @@ func (m SelectBlueprintModel) Init() tea.Cmd {
 	if m.autoValidate {
 		// Dispatch command to select the blueprint file
 		// so the validation model can trigger the validation process.
-		return tea.Batch(fcmd, selectBlueprintCmd(m.selectedFile))
+		return tea.Batch(fcmd, selectBlueprintCmd(consts.BlueprintSourceFile, m.selectedFile))
 	}
 	return fcmd
 }
@@ -83,6 +86,20 @@ func (m SelectBlueprintModel) Init() tea.Cmd {
 func (m SelectBlueprintModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	prevStage := m.stage
 
+	if _, isBackMsg := msg.(backToSelectSourceMsg); isBackMsg {
+		m.stage = selectBlueprintStageSelectSource
+		m.source = ""
+		return m, nil
+	}
+
+	if m.stage == selectBlueprintStageInputFileLocation {
+		// Key presses are captured by the text input for the remote location
+		// so "q" can be typed without quitting.
+		var cmd tea.Cmd
+		m.remotePath, cmd = m.remotePath.Update(msg)
+		return m, cmd
+	}
+
 	cmds := []tea.Cmd{}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -95,6 +112,10 @@ func (m SelectBlueprintModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				i, ok := m.sourceList.SelectedItem().(blueprintSourceItem)
 				if ok {
 					m.source = string(i.key)
+					if blueprint.IsRemoteSource(m.source) {
+						m.stage = selectBlueprintStageInputFileLocation
+						return m, m.remotePath.Focus(m.source)
+					}
 					m.stage = selectBlueprintStageSelectLocalFile
 				}
 			}
@@ -123,7 +144,7 @@ func (m SelectBlueprintModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if didSelect, path := m.filepicker.DidSelectFile(msg); didSelect {
 		m.selectedFile = path
 		// Dispatch comamand with the path of the selected file.
-		cmds = append(cmds, selectBlueprintCmd(path))
+		cmds = append(cmds, selectBlueprintCmd(consts.BlueprintSourceFile, path))
 	}
 
 	// Did the user select a disabled file?
@@ -138,6 +159,13 @@ func (m SelectBlueprintModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handlesQuitKey determines whether a key that would otherwise quit
+// the program is used to go back to a previous step, "esc" returns
+// to the source selection from the remote location input.
+func (m SelectBlueprintModel) handlesQuitKey(msg tea.KeyMsg) bool {
+	return msg.String() == "esc" && m.stage == selectBlueprintStageInputFileLocation
+}
+
 func (m SelectBlueprintModel) View() string {
 	if m.quitting {
 		return ""
@@ -155,6 +183,8 @@ func (m SelectBlueprintModel) View() string {
 		s.WriteString("\n\n" + m.sourceList.View() + "\n")
 	} else if m.stage == selectBlueprintStageSelectLocalFile {
 		s.WriteString("\n\n" + m.filepicker.View() + "\n")
+	} else if m.stage == selectBlueprintStageInputFileLocation {
+		s.WriteString("\n\n" + m.remotePath.View() + "\n")
 	}
 	return s.String()
 }
@@ -184,6 +214,7 @@ func NewSelectBlueprint(
 		autoValidate: autoValidate,
 		selectedFile: blueprintFile,
 		sourceList:   sourceList,
+		remotePath:   NewInputBlueprintRemotePath(celerityStyles),
 		stage:        selectBlueprintStageSelectSource,
 	}, nil
 }
@@ -1,6 +1,7 @@
 package validateui
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -8,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
 	"github.com/newstack-cloud/celerity/apps/cli/internal/tui/styles"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
 	"go.uber.org/zap"
 )
 
@@ -41,8 +43,12 @@ const (
 type MainModel struct {
 	sessionState validateSessionState
 	// validateStage   ValidateStage
-	blueprintFile   string
-	quitting        bool
+	blueprintFile  string
+	blueprintFiles []string
+	quitting       bool
+	// done is set when the program exits after validation has finished
+	// so the outcome of validation is left in the terminal.
+	done            bool
 	selectBlueprint tea.Model
 	validate        tea.Model
 	Error           error
@@ -74,9 +80,21 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, bpCmd, validateCmd)
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc":
-			m.quitting = true
-			return m, tea.Quit
+		case "ctrl+c":
+			return m.quit()
+		case "esc":
+			// The diagnostics browser uses esc to clear the filter
+			// and blueprint selection uses it to go back a step.
+			if m.browserHandlesKey(msg) || m.selectBlueprintHandlesKey(msg) {
+				break
+			}
+			return m.quit()
+		case "q":
+			// Blueprint selection handles "q" itself as it can be typed
+			// as a part of a remote blueprint location.
+			if m.sessionState == validateView && !m.browserHandlesKey(msg) {
+				return m.quit()
+			}
 		}
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -101,14 +119,54 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.validate = validateModel
 		cmds = append(cmds, newCmd)
-		if validateModel.err != nil {
+		// In watch mode, errors are displayed until the next change
+		// and do not cause the command to fail.
+		// The outcome of the latest run is used when validation
+		// is carried out again from the diagnostics browser.
+		m.Error = nil
+		if validateModel.err != nil && validateModel.watcher == nil {
 			log.Println("setting validate model error:", validateModel.err)
 			m.Error = validateModel.err
+		} else if failedErr := validateModel.Failed(); failedErr != nil {
+			m.Error = failedErr
 		}
 	}
 	return m, tea.Batch(cmds...)
 }
 
+// quit exits the program, once validation has finished the summary
+// of the outcome is rendered as the final frame instead of
+// the goodbye message.
+func (m MainModel) quit() (tea.Model, tea.Cmd) {
+	validateModel, ok := m.validate.(ValidateModel)
+	if ok && m.sessionState == validateView && validateModel.completed() {
+		m.done = true
+	} else {
+		m.quitting = true
+	}
+	return m, tea.Quit
+}
+
+func (m MainModel) browserHandlesKey(msg tea.KeyMsg) bool {
+	validateModel, ok := m.validate.(ValidateModel)
+	return ok && m.sessionState == validateView && validateModel.handlesQuitKey(msg)
+}
+
+func (m MainModel) selectBlueprintHandlesKey(msg tea.KeyMsg) bool {
+	selectBlueprintModel, ok := m.selectBlueprint.(SelectBlueprintModel)
+	return ok && m.sessionState == validateBlueprintSelect && selectBlueprintModel.handlesQuitKey(msg)
+}
+
+// Close releases the resources held by the validate app,
+// this stops watching for changes in watch mode.
+func (m MainModel) Close() error {
+	validateModel, ok := m.validate.(ValidateModel)
+	if !ok {
+		return nil
+	}
+	return validateModel.Close()
+}
+
 func (m MainModel) View() string {
 	if m.quitting {
 		return quitTextStyle.Render("Had enough? See you next time.")
@@ -116,19 +174,38 @@ func (m MainModel) View() string {
 	if m.sessionState == validateBlueprintSelect {
 		return m.selectBlueprint.View()
 	}
+	if validateModel, ok := m.validate.(ValidateModel); ok && m.done {
+		return m.selectedView() + validateModel.finalView()
+	}
+	return m.selectedView() + m.validate.View()
+}
+
+func (m MainModel) selectedView() string {
 	selected := "\n  You selected blueprint: " + selectedItemStyle.Render(m.blueprintFile) + "\n"
-	return selected + m.validate.View()
+	if len(m.blueprintFiles) > 1 {
+		selected = "\n  You selected " + selectedItemStyle.Render(
+			fmt.Sprintf("%d blueprint files", len(m.blueprintFiles)),
+		) + "\n"
+	}
+	return selected
 }
 
 func NewValidateApp(
 	engine engine.DeployEngine,
 	logger *zap.Logger,
-	blueprintFile string,
+	opts *validate.Options,
 	isDefaultBlueprintFile bool,
 	celerityStyles *styles.CelerityStyles,
 ) (*MainModel, error) {
+	blueprintFile := ""
+	if len(opts.BlueprintFiles) == 1 {
+		blueprintFile = opts.BlueprintFiles[0]
+	}
 	sessionState := validateBlueprintSelect
-	autoValidate := blueprintFile != "" && !isDefaultBlueprintFile
+	// There is nothing to select when multiple blueprint files
+	// have been provided.
+	autoValidate := len(opts.BlueprintFiles) > 1 ||
+		(blueprintFile != "" && !isDefaultBlueprintFile)
 
 	if autoValidate {
 		sessionState = validateView
@@ -138,10 +215,14 @@ func NewValidateApp(
 	if err != nil {
 		return nil, err
 	}
-	validate := NewValidateModel(engine, logger)
+	validate, err := NewValidateModel(engine, logger, opts)
+	if err != nil {
+		return nil, err
+	}
 	return &MainModel{
 		sessionState:    sessionState,
 		blueprintFile:   blueprintFile,
+		blueprintFiles:  opts.BlueprintFiles,
 		selectBlueprint: selectBlueprint,
 		validate:        validate,
 	}, nil
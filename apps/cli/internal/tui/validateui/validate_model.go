@@ -1,17 +1,25 @@
 package validateui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
 	bpcore "github.com/newstack-cloud/bluelink/libs/blueprint/core"
 	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/validate"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/watch"
 	"go.uber.org/zap"
 )
 
@@ -22,29 +30,50 @@ var (
 	diagnosticLevelInfoStyle  = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#2563eb"))
 	diagnosticMessageStyle    = lipgloss.NewStyle().MarginLeft(2)
 	locationStyle             = lipgloss.NewStyle().MarginLeft(2).Foreground(lipgloss.Color("#4f46e5"))
+	snippetGutterStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#4f46e5"))
+	snippetErrorStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#dc2626"))
+	snippetWarnStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#f97316"))
+	snippetInfoStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#2563eb"))
 )
 
-type ValidateResultMsg *types.BlueprintValidationEvent
+// ValidateResultMsg holds an event from the validation stream
+// for a validation run, a nil event means the stream has been closed.
+type ValidateResultMsg struct {
+	run   int
+	event *types.BlueprintValidationEvent
+}
 
 type ValidateErrMsg struct {
+	run int
 	err error
 }
 
-type ValidateStreamMsg struct{}
+// WatchChangeMsg is sent in watch mode when watched files have changed.
+type WatchChangeMsg struct {
+	changed []string
+}
 
-type item struct {
-	result     *types.BlueprintValidationEvent
-	filterText string
+// WatchErrMsg is sent in watch mode when the file system watcher fails.
+type WatchErrMsg struct {
+	err error
 }
 
-func (i item) FilterValue() string {
-	return i.filterText
+// FileResultMsg holds the result of validating a blueprint file when
+// multiple blueprint files are validated, a nil result means that
+// all the blueprint files have been validated.
+type FileResultMsg struct {
+	run    int
+	result *validate.FileResult
 }
 
+type ValidateStreamMsg struct{}
+
 type ValidateModel struct {
 	spinner       spinner.Model
 	list          list.Model
+	keys          *browserKeyMap
 	engine        engine.DeployEngine
+	source        string
 	blueprintFile string
 	resultStream  chan types.BlueprintValidationEvent
 	collected     []*types.BlueprintValidationEvent
@@ -52,8 +81,42 @@ type ValidateModel struct {
 	streaming     bool
 	err           error
 	width         int
+	height        int
 	finished      bool
-	logger        *zap.Logger
+	counts        validate.DiagnosticCounts
+	failOn        bpcore.DiagnosticLevel
+	remoteEngine  bool
+	// Config loaded from the deploy config file that is sent
+	// with the validation request.
+	deployConfig      *types.BlueprintOperationConfig
+	checkPluginConfig bool
+	opts              *validate.Options
+	// run is incremented each time the blueprint files are validated again,
+	// either on a change in watch mode or when requested from the
+	// diagnostics browser, so messages from the streams of previous
+	// runs can be ignored.
+	run int
+	// ctx is cancelled when validation is started again or the program
+	// exits so requests and streams for the previous run are stopped.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	watcher *watch.Watcher
+	changed []string
+	// fileResults holds the result for each blueprint file when multiple
+	// blueprint files are validated, in the same order as the blueprint files
+	// in the options, results are nil until a file has been validated.
+	fileResults      []*validate.FileResult
+	fileResultStream chan *validate.FileResult
+	// sources holds the contents of local blueprint files
+	// used to render source snippets for diagnostics.
+	sources map[string]*validate.Source
+	// hiddenLevels holds the diagnostic levels that
+	// have been hidden from the diagnostics browser.
+	hiddenLevels map[bpcore.DiagnosticLevel]bool
+	// detail is the pane below the diagnostics browser
+	// that holds the details of the focused diagnostic.
+	detail viewport.Model
+	logger *zap.Logger
 }
 
 func (m ValidateModel) Init() tea.Cmd {
@@ -61,26 +124,71 @@ func (m ValidateModel) Init() tea.Cmd {
 }
 
 func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	previous := m.selectedDiagnostic()
+	newModel, cmd := m.update(msg)
+	return newModel.updateDetail(previous), cmd
+}
+
+func (m ValidateModel) update(msg tea.Msg) (ValidateModel, tea.Cmd) {
 	cmds := []tea.Cmd{}
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
+		m.height = msg.Height
 	case SelectBlueprintMsg:
+		m.source = msg.source
 		m.blueprintFile = msg.blueprintFile
+		m.loadSource()
 		// SelectBlueprintMsg can be sent multiple times, we need to make sure we aren't collecting
 		// duplicate results from the stream by not dispatching commands that will create multiple
 		// consumers.
 		if !m.streaming {
-			cmds = append(cmds, startValidateStreamCmd(m, m.logger), waitForNextResultCmd(m), checkForErrCmd(m))
+			cmds = append(cmds, m.validateCmds()...)
+			if m.watcher != nil {
+				cmds = append(cmds, waitForChangeCmd(m.watcher))
+			}
 		}
 		m.streaming = true
+	case WatchChangeMsg:
+		m = m.startNextRun(msg.changed)
+		cmds = append(cmds, m.validateCmds()...)
+		cmds = append(cmds, waitForChangeCmd(m.watcher), m.list.SetItems([]list.Item{}))
+	case FileResultMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		if msg.result == nil {
+			// The stream is closed without results when validation
+			// could not be started, in which case an error is reported.
+			m.finished = !slices.Contains(m.fileResults, nil)
+			return m.resizeBrowser(), m.quitUnlessInteractive()
+		}
+		index := slices.Index(m.opts.BlueprintFiles, msg.result.BlueprintFile)
+		if index >= 0 {
+			m.fileResults[index] = msg.result
+		}
+		m.sources[msg.result.BlueprintFile] = validate.LoadSource(msg.result.BlueprintFile)
+		cmds = append(cmds, m.list.SetItems(m.visibleItems()), waitForFileResultCmd(m))
+	case WatchErrMsg:
+		m.err = msg.err
+		return m, tea.Quit
 	case ValidateResultMsg:
-		if msg == nil {
+		if msg.run != m.run {
+			return m, nil
+		}
+		if msg.event == nil {
+			m.finished = true
+			return m.resizeBrowser(), m.quitUnlessInteractive()
+		}
+		if msg.event.Message != "" {
+			m.collected = append(m.collected, msg.event)
+			m.counts.Add(msg.event.Diagnostic.Level)
+		}
+		if msg.event.End {
 			m.finished = true
-			return m, tea.Quit
+			return m.resizeBrowser(), tea.Batch(m.list.SetItems(m.visibleItems()), m.quitUnlessInteractive())
 		}
-		m.collected = append(m.collected, msg)
-		setListItemsCmd := m.list.SetItems(listItemsFromResults(m.collected))
+		setListItemsCmd := m.list.SetItems(m.visibleItems())
 		cmds = append(cmds, setListItemsCmd, waitForNextResultCmd(m), checkForErrCmd(m))
 	case spinner.TickMsg:
 		log.Println("ValidateModel: spinner tick")
@@ -88,12 +196,27 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 	case ValidateErrMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = msg.err
-			return m, tea.Quit
+			return m, m.quitUnlessInteractive()
+		}
+	case editorFinishedMsg:
+		if msg.err != nil {
+			return m, m.list.NewStatusMessage(renderError(msg.err))
+		}
+	case tea.KeyMsg:
+		// Keys are part of the filter text while the user is filtering diagnostics.
+		if !m.list.SettingFilter() {
+			if newModel, cmd, handled := m.handleBrowserKey(msg); handled {
+				return newModel.resizeBrowser(), cmd
+			}
 		}
 	}
 
+	m = m.resizeBrowser()
 	var listCmd tea.Cmd
 	m.list, listCmd = m.list.Update(msg)
 	cmds = append(cmds, listCmd)
@@ -101,109 +224,327 @@ func (m ValidateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleBrowserKey handles the keys for the actions of the diagnostics
+// browser, the boolean return value is false for keys that are not
+// handled so they can be passed on to the list.
+func (m ValidateModel) handleBrowserKey(msg tea.KeyMsg) (ValidateModel, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.ToggleErrors):
+		newModel, cmd := m.toggleLevel(bpcore.DiagnosticLevelError)
+		return newModel, cmd, true
+	case key.Matches(msg, m.keys.ToggleWarnings):
+		newModel, cmd := m.toggleLevel(bpcore.DiagnosticLevelWarning)
+		return newModel, cmd, true
+	case key.Matches(msg, m.keys.ToggleInfo):
+		newModel, cmd := m.toggleLevel(bpcore.DiagnosticLevelInfo)
+		return newModel, cmd, true
+	case key.Matches(msg, m.keys.DetailDown):
+		m.detail.ScrollDown(1)
+		return m, nil, true
+	case key.Matches(msg, m.keys.DetailUp):
+		m.detail.ScrollUp(1)
+		return m, nil, true
+	case key.Matches(msg, m.keys.Open):
+		return m, m.openInEditorCmd(), true
+	case key.Matches(msg, m.keys.Rerun):
+		// Results from multiple runs would be mixed together
+		// if validation was started again before the current run finishes.
+		if !m.finished && m.err == nil {
+			return m, nil, true
+		}
+		m = m.startNextRun(nil)
+		cmds := append(m.validateCmds(), m.list.SetItems([]list.Item{}))
+		return m, tea.Batch(cmds...), true
+	}
+
+	return m, nil, false
+}
+
+func (m ValidateModel) validateCmds() []tea.Cmd {
+	if m.multipleFiles() {
+		return []tea.Cmd{startValidateFilesCmd(m, m.logger), waitForFileResultCmd(m)}
+	}
+
+	return []tea.Cmd{startValidateStreamCmd(m, m.logger), waitForNextResultCmd(m), checkForErrCmd(m)}
+}
+
+// openInEditorCmd opens the blueprint file of the focused diagnostic
+// at the line the diagnostic refers to in the user's editor.
+func (m ValidateModel) openInEditorCmd() tea.Cmd {
+	i, ok := m.list.SelectedItem().(diagnosticItem)
+	if !ok {
+		return nil
+	}
+
+	source := m.sources[i.blueprintFile]
+	if source == nil {
+		return m.list.NewStatusMessage(
+			renderError(errors.New("only local blueprint files can be opened in an editor")),
+		)
+	}
+
+	line, column := 1, 1
+	if validate.HasPreciseRange(i.diagnostic.Range) {
+		line, column = i.diagnostic.Range.Start.Line, i.diagnostic.Range.Start.Column
+	}
+	cmd, err := editorCommand(source.Path(), line, column)
+	if err != nil {
+		return m.list.NewStatusMessage(renderError(err))
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err}
+	})
+}
+
+// startNextRun resets the model for validating the blueprint files again,
+// diagnostics from the previous run are replaced by the diagnostics
+// from the new run.
+func (m ValidateModel) startNextRun(changed []string) ValidateModel {
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.run += 1
+	m.changed = changed
+	m.collected = nil
+	m.counts = validate.DiagnosticCounts{}
+	m.finished = false
+	m.err = nil
+	m.resultStream = make(chan types.BlueprintValidationEvent)
+	m.errStream = make(chan error)
+	m.fileResults = make([]*validate.FileResult, len(m.opts.BlueprintFiles))
+	m.fileResultStream = make(chan *validate.FileResult, len(m.opts.BlueprintFiles))
+	// Blueprint files are loaded again as they may have changed.
+	m.sources = map[string]*validate.Source{}
+	m.loadSource()
+	return m
+}
+
+// loadSource loads the selected blueprint file when it is a local file
+// so source snippets can be rendered for diagnostics.
+func (m ValidateModel) loadSource() {
+	if m.multipleFiles() || blueprint.IsRemoteSource(m.source) {
+		return
+	}
+
+	m.sources[m.blueprintFile] = validate.LoadSource(m.blueprintFile)
+}
+
+func (m ValidateModel) multipleFiles() bool {
+	return len(m.opts.BlueprintFiles) > 1
+}
+
+// quitUnlessInteractive quits once the first validation run has finished
+// unless there are diagnostics to browse or the user is watching for changes.
+// Validation that is started again from the diagnostics browser
+// never quits so the user can keep browsing the results.
+func (m ValidateModel) quitUnlessInteractive() tea.Cmd {
+	if m.watcher != nil || m.run > 0 || len(m.allItems()) > 0 {
+		return nil
+	}
+	return tea.Quit
+}
+
+// handlesQuitKey determines whether a key that would otherwise quit
+// the program is used by the diagnostics browser, "q" is part of the
+// filter text while filtering and "esc" clears the current filter.
+func (m ValidateModel) handlesQuitKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "q":
+		return m.list.SettingFilter()
+	case "esc":
+		return m.list.FilterState() != list.Unfiltered
+	}
+	return false
+}
+
 func (m ValidateModel) View() string {
 	log.Printf("ValidateModel: Rendering view m.collected length: %d", len(m.collected))
 	if m.err != nil {
-		return renderError(m.err)
+		hint := ""
+		if m.watcher == nil {
+			hint = diagnosticMessageStyle.Render("Press r to validate again or q to quit") + "\n\n"
+		}
+		return renderError(m.err) + "\n" + hint + m.watchingView()
 	}
 
 	sb := strings.Builder{}
+	if len(m.changed) > 0 {
+		sb.WriteString(
+			diagnosticMessageStyle.Render("Change detected in " + strings.Join(m.changed, ", ")),
+		)
+		sb.WriteString("\n")
+	}
 
-	for _, result := range m.collected {
-		containerStyle := lipgloss.NewStyle().Padding(1, 1).Width(m.width)
-
-		itemSB := strings.Builder{}
-		itemSB.WriteString(validateCategroyStyle.Render("diagnostic"))
-		if result.Diagnostic.Level == bpcore.DiagnosticLevelError {
-			itemSB.WriteString(
-				diagnosticLevelErrorStyle.Render(
-					diagnosticLevelName(result.Diagnostic.Level),
-				),
-			)
-		} else if result.Diagnostic.Level == bpcore.DiagnosticLevelWarning {
-			itemSB.WriteString(
-				diagnosticLevelWarnStyle.Render(
-					diagnosticLevelName(result.Diagnostic.Level),
-				),
-			)
-		} else if result.Diagnostic.Level == bpcore.DiagnosticLevelInfo {
-			itemSB.WriteString(
-				diagnosticLevelInfoStyle.Render(
-					diagnosticLevelName(result.Diagnostic.Level),
-				),
-			)
-		}
-		itemSB.WriteString(diagnosticMessageStyle.Render(result.Diagnostic.Message))
-		if hasPreciseRange(result.Diagnostic.Range) {
-			itemSB.WriteString(
-				locationStyle.Render(
-					fmt.Sprintf("(line %d, column %d)", result.Diagnostic.Range.Start.Line, result.Diagnostic.Range.Start.Column),
-				),
-			)
-		}
-		containerRendered := containerStyle.Render(itemSB.String())
-		sb.WriteString(containerRendered)
+	if len(m.allItems()) > 0 {
 		sb.WriteString("\n")
+		sb.WriteString(m.headerView())
+		sb.WriteString("\n\n")
+		sb.WriteString(m.list.View())
+		sb.WriteString("\n\n")
+		sb.WriteString(m.detailView())
+		sb.WriteString("\n")
+	} else if !m.finished {
+		sb.WriteString(fmt.Sprintf("\n\n %s %s\n\n", m.spinner.View(), m.progressText()))
+		return sb.String()
 	}
-	if !m.finished {
-		sb.WriteString(fmt.Sprintf("\n\n %s Validating project...\n\n", m.spinner.View()))
+
+	if m.finished {
+		sb.WriteString(m.summaryView())
 	}
+	sb.WriteString(m.watchingView())
 	return sb.String()
 }
 
-func NewValidateModel(engine engine.DeployEngine, logger *zap.Logger) ValidateModel {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	return ValidateModel{
-		spinner:      s,
-		engine:       engine,
-		logger:       logger,
-		list:         list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
-		resultStream: make(chan types.BlueprintValidationEvent),
-		errStream:    make(chan error),
+// completed determines whether validation has finished,
+// either with results or with an error.
+func (m ValidateModel) completed() bool {
+	return m.finished || m.err != nil
+}
+
+// finalView renders the outcome of validation that is left in the
+// terminal when the program exits, the diagnostics browser is
+// interactive so only the summary is kept.
+func (m ValidateModel) finalView() string {
+	if m.err != nil {
+		return renderError(m.err) + "\n"
 	}
+	return m.summaryView()
 }
 
-func diagnosticLevelName(level bpcore.DiagnosticLevel) string {
-	switch level {
-	case bpcore.DiagnosticLevelError:
-		return "error"
-	case bpcore.DiagnosticLevelWarning:
-		return "warning"
-	case bpcore.DiagnosticLevelInfo:
-		return "info"
-	default:
-		return "unknown"
+// summaryView renders the outcome of validation once it has finished,
+// when multiple blueprint files are validated, a summary is included
+// for each file with diagnostics or that could not be validated.
+func (m ValidateModel) summaryView() string {
+	sb := strings.Builder{}
+	sb.WriteString("\n")
+	if !m.multipleFiles() {
+		summary := diagnosticMessageStyle.Render("Validation complete with " + m.counts.String())
+		if m.Failed() != nil {
+			summary = diagnosticLevelErrorStyle.Render(m.Failed().Error())
+		}
+		sb.WriteString(summary)
+		sb.WriteString("\n\n")
+		return sb.String()
 	}
+
+	for _, result := range m.fileResults {
+		if result.Err == nil && len(result.Diagnostics) == 0 {
+			continue
+		}
+		sb.WriteString(diagnosticMessageStyle.Render(validate.ResultSummary(result)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	summary := diagnosticMessageStyle.Render(
+		fmt.Sprintf(
+			"Validation of %d blueprint files complete with %s",
+			len(m.fileResults),
+			validate.TotalCounts(m.fileResults).String(),
+		),
+	)
+	if m.Failed() != nil {
+		summary = diagnosticLevelErrorStyle.Render(m.Failed().Error())
+	}
+	sb.WriteString(summary)
+	sb.WriteString("\n\n")
+	return sb.String()
 }
 
-func hasPreciseRange(r *bpcore.DiagnosticRange) bool {
-	return r != nil && r.Start.Line > 0 && r.Start.Column > 0
+func (m ValidateModel) watchingView() string {
+	if m.watcher == nil {
+		return ""
+	}
+
+	return diagnosticMessageStyle.Render("Watching for changes, press ctrl+c to exit") + "\n\n"
 }
 
-func listItemsFromResults(results []*types.BlueprintValidationEvent) []list.Item {
-	items := []list.Item{}
-	for _, result := range results {
-		items = append(items, item{
-			result:     result,
-			filterText: resultToPlainText(result),
-		})
+// Close stops the current validation run and
+// stops watching for changes in watch mode.
+func (m ValidateModel) Close() error {
+	m.cancel()
+	if m.watcher == nil {
+		return nil
 	}
-	return items
+	return m.watcher.Close()
 }
 
-func resultToPlainText(result *types.BlueprintValidationEvent) string {
-	sb := strings.Builder{}
-	sb.WriteString("diagnostic")
-	sb.WriteString(" ")
-	sb.WriteString(diagnosticLevelName(result.Diagnostic.Level))
-	sb.WriteString(" ")
-	sb.WriteString(result.Diagnostic.Message)
-	if hasPreciseRange(result.Diagnostic.Range) {
-		sb.WriteString(fmt.Sprintf(" (line %d, column %d)", result.Diagnostic.Range.Start.Line, result.Diagnostic.Range.Start.Column))
+// Failed returns a validate.DiagnosticsError when the validation process
+// has finished and produced diagnostics at or above the fail on level.
+// Validation does not fail in watch mode as the blueprint is validated
+// again on each change until the user exits.
+func (m ValidateModel) Failed() error {
+	if !m.finished || m.watcher != nil {
+		return nil
+	}
+
+	if m.multipleFiles() {
+		return validate.CheckResults(m.fileResults, m.failOn)
+	}
+
+	return validate.CheckDiagnostics(&m.counts, m.failOn)
+}
+
+func NewValidateModel(
+	engine engine.DeployEngine,
+	logger *zap.Logger,
+	opts *validate.Options,
+) (ValidateModel, error) {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	var watcher *watch.Watcher
+	if opts.Watch {
+		var err error
+		watcher, err = watch.New(watch.DefaultDebounce)
+		if err != nil {
+			return ValidateModel{}, err
+		}
+	}
+
+	keys := newBrowserKeyMap()
+	ctx, cancel := context.WithCancel(context.Background())
+	return ValidateModel{
+		ctx:               ctx,
+		cancel:            cancel,
+		spinner:           s,
+		engine:            engine,
+		logger:            logger,
+		failOn:            opts.FailOn,
+		remoteEngine:      opts.RemoteEngine,
+		deployConfig:      opts.DeployConfig,
+		checkPluginConfig: opts.CheckPluginConfig,
+		opts:              opts,
+		watcher:           watcher,
+		keys:              keys,
+		list:              newDiagnosticsBrowser(keys),
+		detail:            newDetailPane(),
+		resultStream:      make(chan types.BlueprintValidationEvent),
+		errStream:         make(chan error),
+		fileResults:       make([]*validate.FileResult, len(opts.BlueprintFiles)),
+		fileResultStream:  make(chan *validate.FileResult, len(opts.BlueprintFiles)),
+		sources:           map[string]*validate.Source{},
+		hiddenLevels:      map[bpcore.DiagnosticLevel]bool{},
+	}, nil
+}
+
+func snippetStyleForLevel(level bpcore.DiagnosticLevel) *validate.SnippetStyle {
+	highlightStyle := snippetInfoStyle
+	switch level {
+	case bpcore.DiagnosticLevelError:
+		highlightStyle = snippetErrorStyle
+	case bpcore.DiagnosticLevelWarning:
+		highlightStyle = snippetWarnStyle
+	}
+
+	return &validate.SnippetStyle{
+		Gutter: func(value string) string {
+			return snippetGutterStyle.Render(value)
+		},
+		Highlight: func(value string) string {
+			return highlightStyle.Render(value)
+		},
 	}
-	return sb.String()
 }
 
 func renderError(err error) string {
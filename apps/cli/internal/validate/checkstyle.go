@@ -0,0 +1,56 @@
+package validate
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const checkstyleSource = "celerity.validate"
+
+type checkstyleReport struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string             `xml:"name,attr"`
+	Errors []*checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func writeCheckstyle(writer io.Writer, results []*FileResult) error {
+	report := &checkstyleReport{
+		Version: "4.3",
+		Files:   []*checkstyleFile{},
+	}
+
+	for _, result := range results {
+		file := &checkstyleFile{
+			Name:   result.BlueprintFile,
+			Errors: []*checkstyleError{},
+		}
+		for _, diagnostic := range withFileErrors(result) {
+			checkstyleErr := &checkstyleError{
+				Severity: DiagnosticLevelName(diagnostic.Level),
+				Message:  diagnostic.Message,
+				Source:   checkstyleSource,
+			}
+			if HasPreciseRange(diagnostic.Range) {
+				checkstyleErr.Line = diagnostic.Range.Start.Line
+				checkstyleErr.Column = diagnostic.Range.Start.Column
+			}
+			file.Errors = append(file.Errors, checkstyleErr)
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	return writeXML(writer, report)
+}
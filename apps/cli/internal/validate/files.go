@@ -0,0 +1,227 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultParallelism is the default maximum number of blueprint
+	// files that are validated at the same time.
+	DefaultParallelism = 4
+)
+
+// FileResult holds the outcome of validating a single blueprint file.
+type FileResult struct {
+	BlueprintFile string
+	Diagnostics   []*core.Diagnostic
+	Counts        *DiagnosticCounts
+	// Err is set when the validation process could not be
+	// carried out for the blueprint file.
+	Err error
+}
+
+// ValidateFile validates a single blueprint file with the deploy engine,
+// collecting the diagnostics from the validation stream.
+// onDiagnostic is optional and is called for each diagnostic as it is received.
+func ValidateFile(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	blueprintFile string,
+	logger *zap.Logger,
+	onDiagnostic func(*core.Diagnostic),
+) *FileResult {
+	result := &FileResult{
+		BlueprintFile: blueprintFile,
+		Diagnostics:   []*core.Diagnostic{},
+		Counts:        &DiagnosticCounts{},
+	}
+
+	docInfo, err := blueprint.ResolveDocumentInfo(blueprintFile, opts.RemoteEngine)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	blueprintValidation, err := deployEngine.CreateBlueprintValidation(
+		ctx,
+		&types.CreateBlueprintValidationPayload{
+			BlueprintDocumentInfo: docInfo,
+			Config:                opts.DeployConfig,
+		},
+		&types.CreateBlueprintValidationQuery{
+			CheckPluginConfig: opts.CheckPluginConfig,
+		},
+	)
+	if err != nil {
+		result.Err = engine.SimplifyError(err, logger)
+		return result
+	}
+
+	streamTo := make(chan types.BlueprintValidationEvent)
+	errChan := make(chan error)
+	err = deployEngine.StreamBlueprintValidationEvents(
+		ctx,
+		blueprintValidation.ID,
+		streamTo,
+		errChan,
+	)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			return result
+		case err := <-errChan:
+			if err != nil {
+				result.Err = engine.SimplifyError(err, logger)
+				return result
+			}
+		case event, open := <-streamTo:
+			if !open {
+				return result
+			}
+
+			if event.Message != "" {
+				diagnostic := event.Diagnostic
+				result.Diagnostics = append(result.Diagnostics, &diagnostic)
+				result.Counts.Add(diagnostic.Level)
+				if onDiagnostic != nil {
+					onDiagnostic(&diagnostic)
+				}
+			}
+
+			if event.End {
+				return result
+			}
+		}
+	}
+}
+
+// ValidateFiles validates the blueprint files in the provided options
+// concurrently, with at most Options.Parallelism validations in progress
+// at the same time.
+// onResult is optional and is called once the validation of each blueprint
+// file has finished, calls to onResult are never made concurrently.
+// Results are returned in the same order as the blueprint files.
+func ValidateFiles(
+	ctx context.Context,
+	deployEngine engine.DeployEngine,
+	opts *Options,
+	logger *zap.Logger,
+	onResult func(*FileResult),
+) []*FileResult {
+	results := make([]*FileResult, len(opts.BlueprintFiles))
+	slots := make(chan struct{}, max(opts.Parallelism, 1))
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for i, blueprintFile := range opts.BlueprintFiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			result := ValidateFile(ctx, deployEngine, opts, blueprintFile, logger, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = result
+			if onResult != nil {
+				onResult(result)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// TotalCounts returns the number of diagnostics at each level
+// across all the provided results.
+func TotalCounts(results []*FileResult) *DiagnosticCounts {
+	total := &DiagnosticCounts{}
+	for _, result := range results {
+		total.Errors += result.Counts.Errors
+		total.Warnings += result.Counts.Warnings
+		total.Info += result.Counts.Info
+	}
+	return total
+}
+
+// FilesError is returned when the validation process could not
+// be carried out for one or more blueprint files.
+type FilesError struct {
+	Failed int
+	Total  int
+}
+
+func (e *FilesError) Error() string {
+	return fmt.Sprintf(
+		"validation could not be carried out for %d of %d blueprint files",
+		e.Failed,
+		e.Total,
+	)
+}
+
+// CheckResults returns a FilesError when the validation process could not be
+// carried out for any of the blueprint files, otherwise a DiagnosticsError
+// is returned when diagnostics were received across all files at or above
+// the provided fail on level.
+func CheckResults(results []*FileResult, failOn core.DiagnosticLevel) error {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed += 1
+		}
+	}
+	if failed > 0 {
+		return &FilesError{
+			Failed: failed,
+			Total:  len(results),
+		}
+	}
+
+	return CheckDiagnostics(TotalCounts(results), failOn)
+}
+
+// ResultSummary produces a single line summary of the outcome
+// of validating a blueprint file.
+func ResultSummary(result *FileResult) string {
+	if result.Err != nil {
+		return fmt.Sprintf("%s: failed: %s", result.BlueprintFile, result.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", result.BlueprintFile, result.Counts.String())
+}
+
+// withFileErrors returns the diagnostics for a result including an error
+// diagnostic for a blueprint file that could not be validated so
+// failures are included in reports for machine-readable formats.
+func withFileErrors(result *FileResult) []*core.Diagnostic {
+	if result.Err == nil {
+		return result.Diagnostics
+	}
+
+	return append(
+		slices.Clone(result.Diagnostics),
+		&core.Diagnostic{
+			Level:   core.DiagnosticLevelError,
+			Message: fmt.Sprintf("validation could not be carried out: %s", result.Err),
+		},
+	)
+}
@@ -0,0 +1,162 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint-state/manage"
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/engine"
+	"go.uber.org/zap"
+)
+
+// fakeDeployEngine validates blueprint files by sending a single
+// warning with the name of the blueprint file after a delay
+// that is configured for each blueprint file.
+type fakeDeployEngine struct {
+	engine.DeployEngine
+	delays map[string]time.Duration
+
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (e *fakeDeployEngine) CreateBlueprintValidation(
+	ctx context.Context,
+	payload *types.CreateBlueprintValidationPayload,
+	query *types.CreateBlueprintValidationQuery,
+) (*manage.BlueprintValidation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.active += 1
+	e.maxActive = max(e.maxActive, e.active)
+
+	return &manage.BlueprintValidation{
+		ID: payload.BlueprintDocumentInfo.BlueprintFile,
+	}, nil
+}
+
+func (e *fakeDeployEngine) StreamBlueprintValidationEvents(
+	ctx context.Context,
+	validationID string,
+	streamTo chan<- types.BlueprintValidationEvent,
+	errChan chan<- error,
+) error {
+	go func() {
+		time.Sleep(e.delays[validationID])
+
+		e.mu.Lock()
+		e.active -= 1
+		e.mu.Unlock()
+
+		event := types.BlueprintValidationEvent{}
+		event.Diagnostic = core.Diagnostic{
+			Level:   core.DiagnosticLevelWarning,
+			Message: validationID,
+		}
+		streamTo <- event
+		streamTo <- types.BlueprintValidationEvent{End: true}
+	}()
+
+	return nil
+}
+
+func TestValidateFilesKeepsInputOrder(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"a.blueprint.yaml",
+		"b.blueprint.yaml",
+		"c.blueprint.yaml",
+		"d.blueprint.yaml",
+		"e.blueprint.yaml",
+		"f.blueprint.yaml",
+	}
+
+	blueprintFiles := []string{}
+	delays := map[string]time.Duration{}
+	for i, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("version: 2025-05-12\n"), 0644); err != nil {
+			t.Fatalf("failed to write blueprint file: %v", err)
+		}
+		blueprintFiles = append(blueprintFiles, path)
+		// Files later in the list finish first.
+		delays[name] = time.Duration(len(names)-i) * 20 * time.Millisecond
+	}
+	// A file that can not be validated keeps its position in the results.
+	missingFile := filepath.Join(dir, "missing.blueprint.yaml")
+	blueprintFiles = slices.Insert(blueprintFiles, 2, missingFile)
+
+	deployEngine := &fakeDeployEngine{delays: delays}
+	opts := &Options{
+		BlueprintFiles: blueprintFiles,
+		Parallelism:    3,
+	}
+
+	finished := []string{}
+	results := ValidateFiles(
+		context.Background(),
+		deployEngine,
+		opts,
+		zap.NewNop(),
+		func(result *FileResult) {
+			finished = append(finished, result.BlueprintFile)
+		},
+	)
+
+	if len(results) != len(blueprintFiles) {
+		t.Fatalf("expected %d results, got %d", len(blueprintFiles), len(results))
+	}
+
+	for i, result := range results {
+		if result.BlueprintFile != blueprintFiles[i] {
+			t.Errorf("result %d: expected %q, got %q", i, blueprintFiles[i], result.BlueprintFile)
+			continue
+		}
+
+		if result.BlueprintFile == missingFile {
+			if result.Err == nil {
+				t.Errorf("result %d: expected an error for a blueprint file that does not exist", i)
+			}
+			continue
+		}
+
+		if result.Err != nil {
+			t.Errorf("result %d: unexpected error: %v", i, result.Err)
+			continue
+		}
+		if len(result.Diagnostics) != 1 ||
+			result.Diagnostics[0].Message != filepath.Base(result.BlueprintFile) ||
+			result.Counts.Warnings != 1 {
+			t.Errorf("result %d: expected a single warning for %q, got %v", i, result.BlueprintFile, result.Diagnostics)
+		}
+	}
+
+	if len(finished) != len(blueprintFiles) {
+		t.Errorf("expected onResult to be called for each blueprint file, got %d calls", len(finished))
+	}
+	if slices.Equal(finished, blueprintFiles) {
+		t.Error("expected blueprint files to finish out of order to check the results are reordered")
+	}
+
+	if deployEngine.maxActive < 2 || deployEngine.maxActive > opts.Parallelism {
+		t.Errorf(
+			"expected between 2 and %d validations at the same time, got %d",
+			opts.Parallelism,
+			deployEngine.maxActive,
+		)
+	}
+
+	var filesErr *FilesError
+	if err := CheckResults(results, core.DiagnosticLevelError); !errors.As(err, &filesErr) || filesErr.Failed != 1 {
+		t.Errorf("expected a FilesError for 1 blueprint file, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+// See: https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions
+var (
+	githubDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	githubPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+func writeGitHub(writer io.Writer, results []*FileResult) error {
+	for _, result := range results {
+		if err := writeGitHubFile(writer, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeGitHubFile(writer io.Writer, result *FileResult) error {
+	for _, diagnostic := range withFileErrors(result) {
+		properties := []string{
+			fmt.Sprintf("file=%s", githubPropertyEscaper.Replace(result.BlueprintFile)),
+		}
+		if HasPreciseRange(diagnostic.Range) {
+			endLine, endColumn := endPosition(diagnostic.Range)
+			properties = append(
+				properties,
+				fmt.Sprintf("line=%d", diagnostic.Range.Start.Line),
+				fmt.Sprintf("col=%d", diagnostic.Range.Start.Column),
+				fmt.Sprintf("endLine=%d", endLine),
+				fmt.Sprintf("endColumn=%d", endColumn),
+			)
+		}
+
+		_, err := fmt.Fprintf(
+			writer,
+			"::%s %s::%s\n",
+			githubCommand(diagnostic.Level),
+			strings.Join(properties, ","),
+			githubDataEscaper.Replace(diagnostic.Message),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func githubCommand(level core.DiagnosticLevel) string {
+	switch level {
+	case core.DiagnosticLevelError:
+		return "error"
+	case core.DiagnosticLevelWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
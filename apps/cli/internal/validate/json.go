@@ -0,0 +1,92 @@
+package validate
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+type jsonReport struct {
+	BlueprintFile string            `json:"blueprintFile"`
+	Diagnostics   []*jsonDiagnostic `json:"diagnostics"`
+	Summary       *jsonSummary      `json:"summary,omitempty"`
+}
+
+// jsonFilesReport is written when more than one blueprint file
+// is validated, a single blueprint file is written as a jsonReport
+// so the output stays the same for the most common case.
+type jsonFilesReport struct {
+	Files   []*jsonReport `json:"files"`
+	Summary *jsonSummary  `json:"summary"`
+}
+
+type jsonSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+	// Failed is only set for a blueprint file that could not be validated.
+	Failed bool `json:"failed,omitempty"`
+	// FailedFiles is only set for the summary of all blueprint files.
+	FailedFiles *int `json:"failedFiles,omitempty"`
+}
+
+type jsonDiagnostic struct {
+	Level   string                `json:"level"`
+	Message string                `json:"message"`
+	Range   *core.DiagnosticRange `json:"range,omitempty"`
+}
+
+func writeJSON(writer io.Writer, results []*FileResult) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if len(results) == 1 {
+		return encoder.Encode(toJSONReport(results[0], false))
+	}
+
+	report := &jsonFilesReport{
+		Files: []*jsonReport{},
+	}
+	failedFiles := 0
+	for _, result := range results {
+		report.Files = append(report.Files, toJSONReport(result, true))
+		if result.Err != nil {
+			failedFiles += 1
+		}
+	}
+	total := TotalCounts(results)
+	report.Summary = &jsonSummary{
+		Errors:      total.Errors,
+		Warnings:    total.Warnings,
+		Info:        total.Info,
+		FailedFiles: &failedFiles,
+	}
+
+	return encoder.Encode(report)
+}
+
+func toJSONReport(result *FileResult, withSummary bool) *jsonReport {
+	report := &jsonReport{
+		BlueprintFile: result.BlueprintFile,
+		Diagnostics:   []*jsonDiagnostic{},
+	}
+	for _, diagnostic := range withFileErrors(result) {
+		report.Diagnostics = append(report.Diagnostics, &jsonDiagnostic{
+			Level:   DiagnosticLevelName(diagnostic.Level),
+			Message: diagnostic.Message,
+			Range:   diagnostic.Range,
+		})
+	}
+
+	if withSummary {
+		report.Summary = &jsonSummary{
+			Errors:   result.Counts.Errors,
+			Warnings: result.Counts.Warnings,
+			Info:     result.Counts.Info,
+			Failed:   result.Err != nil,
+		}
+	}
+
+	return report
+}
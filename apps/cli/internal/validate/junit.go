@@ -0,0 +1,110 @@
+package validate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a test suite for each blueprint file with a test case
+// for each diagnostic where error diagnostics are reported as failures.
+// When there are no diagnostics, a single passing test case is written
+// for the blueprint file so test reporters show that validation ran.
+func writeJUnit(writer io.Writer, results []*FileResult) error {
+	report := &junitTestSuites{
+		TestSuites: []*junitTestSuite{},
+	}
+
+	for _, result := range results {
+		suite := junitSuiteForFile(result, len(results) > 1)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.TestSuites = append(report.TestSuites, suite)
+	}
+
+	return writeXML(writer, report)
+}
+
+func junitSuiteForFile(result *FileResult, multipleFiles bool) *junitTestSuite {
+	blueprintFile := result.BlueprintFile
+	suiteName := "celerity blueprint validation"
+	if multipleFiles {
+		suiteName = fmt.Sprintf("%s: %s", suiteName, blueprintFile)
+	}
+	suite := &junitTestSuite{
+		Name:      suiteName,
+		TestCases: []*junitTestCase{},
+	}
+
+	for i, diagnostic := range withFileErrors(result) {
+		testCase := &junitTestCase{
+			Name:      fmt.Sprintf("%s %d: %s", DiagnosticLevelName(diagnostic.Level), i+1, diagnostic.Message),
+			ClassName: blueprintFile,
+		}
+		if diagnostic.Level == core.DiagnosticLevelError {
+			testCase.Failure = &junitFailure{
+				Message: diagnostic.Message,
+				Type:    DiagnosticLevelName(diagnostic.Level),
+				Text:    DiagnosticToPlainText(diagnostic),
+			}
+			suite.Failures += 1
+		} else {
+			testCase.SystemOut = DiagnosticToPlainText(diagnostic)
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if len(suite.TestCases) == 0 {
+		suite.TestCases = append(suite.TestCases, &junitTestCase{
+			Name:      "blueprint is valid",
+			ClassName: blueprintFile,
+		})
+	}
+	suite.Tests = len(suite.TestCases)
+
+	return suite
+}
+
+func writeXML(writer io.Writer, report any) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
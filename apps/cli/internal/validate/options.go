@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/bluelink/libs/deploy-engine-client/types"
+)
+
+// Options holds the options for validating a blueprint
+// with the deploy engine.
+type Options struct {
+	// BlueprintFiles holds the locations of the blueprint files to validate,
+	// glob patterns are expanded before the options are created.
+	BlueprintFiles []string
+	// Parallelism is the maximum number of blueprint files
+	// that are validated at the same time.
+	Parallelism int
+	// RemoteEngine should be set when the deploy engine runs on
+	// a different machine and can not read local blueprint files.
+	RemoteEngine bool
+	Format       Format
+	FailOn       core.DiagnosticLevel
+	// DeployConfig is the config loaded from the deploy config file
+	// that is sent to the deploy engine, this is nil when there is
+	// no deploy config file.
+	DeployConfig *types.BlueprintOperationConfig
+	// CheckPluginConfig determines whether the deploy engine should check
+	// the plugin configuration in the deploy config against the schemas
+	// for each provider and transformer plugin.
+	CheckPluginConfig bool
+	// Hyperlinks determines whether blueprint file locations in diagnostics
+	// are written as clickable OSC 8 hyperlinks.
+	Hyperlinks bool
+	// Watch determines whether the blueprint should be validated again each time
+	// the blueprint file, its child blueprints or the deploy config files change.
+	Watch bool
+	// WatchFiles holds the paths of local files other than blueprint files
+	// that are watched in watch mode, such as deploy config and var files.
+	WatchFiles []string
+	// LoadDeployConfig is used to load the deploy config again each time
+	// the blueprint is validated again, in watch mode or when requested
+	// from the interactive UI, so changes to the deploy config files are picked up.
+	LoadDeployConfig func() (*types.BlueprintOperationConfig, error)
+}
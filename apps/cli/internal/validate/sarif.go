@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifRuleID  = "blueprint-validation"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion           `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func writeSARIF(writer io.Writer, fileResults []*FileResult) error {
+	results := []*sarifResult{}
+	for _, fileResult := range fileResults {
+		for _, diagnostic := range withFileErrors(fileResult) {
+			results = append(results, &sarifResult{
+				RuleID:  sarifRuleID,
+				Level:   sarifLevel(diagnostic.Level),
+				Message: &sarifMessage{Text: diagnostic.Message},
+				Locations: []*sarifLocation{
+					{
+						PhysicalLocation: &sarifPhysicalLocation{
+							ArtifactLocation: &sarifArtifactLocation{URI: fileResult.BlueprintFile},
+							Region:           sarifRegionFromRange(diagnostic.Range),
+						},
+					},
+				},
+			})
+		}
+	}
+
+	log := &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []*sarifRun{
+			{
+				Tool: &sarifTool{
+					Driver: &sarifDriver{
+						Name:           "celerity",
+						InformationURI: "https://celerityframework.io",
+						Rules: []*sarifRule{
+							{
+								ID:               sarifRuleID,
+								ShortDescription: &sarifMessage{Text: "Celerity blueprint validation"},
+							},
+						},
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func sarifRegionFromRange(r *core.DiagnosticRange) *sarifRegion {
+	if !HasPreciseRange(r) {
+		return nil
+	}
+
+	endLine, endColumn := endPosition(r)
+	return &sarifRegion{
+		StartLine:   r.Start.Line,
+		StartColumn: r.Start.Column,
+		EndLine:     endLine,
+		EndColumn:   endColumn,
+	}
+}
+
+func sarifLevel(level core.DiagnosticLevel) string {
+	switch level {
+	case core.DiagnosticLevelError:
+		return "error"
+	case core.DiagnosticLevelWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
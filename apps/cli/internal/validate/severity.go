@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+const (
+	// ExitCodeDiagnostics is the exit code used by the validate command
+	// when the engine reports one or more diagnostics at or above
+	// the severity level provided with --fail-on.
+	// An exit code of 1 is reserved for failures to carry out
+	// the validation process itself.
+	ExitCodeDiagnostics = 2
+)
+
+var (
+	failOnLevels = map[string]core.DiagnosticLevel{
+		"error":   core.DiagnosticLevelError,
+		"warning": core.DiagnosticLevelWarning,
+		"info":    core.DiagnosticLevelInfo,
+	}
+)
+
+// ParseFailOn parses the minimum severity of diagnostics
+// that should cause validation to fail.
+func ParseFailOn(value string) (core.DiagnosticLevel, error) {
+	level, ok := failOnLevels[value]
+	if !ok {
+		return 0, fmt.Errorf(
+			"unsupported fail on level %q, must be one of \"error\", \"warning\" or \"info\"",
+			value,
+		)
+	}
+
+	return level, nil
+}
+
+// DiagnosticCounts holds the number of diagnostics
+// received for each diagnostic level.
+type DiagnosticCounts struct {
+	Errors   int
+	Warnings int
+	Info     int
+}
+
+// Add records a diagnostic with the provided level.
+func (c *DiagnosticCounts) Add(level core.DiagnosticLevel) {
+	switch level {
+	case core.DiagnosticLevelError:
+		c.Errors += 1
+	case core.DiagnosticLevelWarning:
+		c.Warnings += 1
+	case core.DiagnosticLevelInfo:
+		c.Info += 1
+	}
+}
+
+// AtOrAbove returns the number of diagnostics that are at least
+// as severe as the provided level.
+func (c *DiagnosticCounts) AtOrAbove(level core.DiagnosticLevel) int {
+	count := c.Errors
+	if level >= core.DiagnosticLevelWarning {
+		count += c.Warnings
+	}
+	if level >= core.DiagnosticLevelInfo {
+		count += c.Info
+	}
+	return count
+}
+
+func (c *DiagnosticCounts) String() string {
+	return fmt.Sprintf("%d errors, %d warnings, %d info", c.Errors, c.Warnings, c.Info)
+}
+
+// DiagnosticsError is returned when validation produced diagnostics
+// at or above the severity level that should cause validation to fail.
+type DiagnosticsError struct {
+	Counts *DiagnosticCounts
+	FailOn core.DiagnosticLevel
+}
+
+func (e *DiagnosticsError) Error() string {
+	return fmt.Sprintf(
+		"validation failed with %s (failing on %s and above)",
+		e.Counts.String(),
+		DiagnosticLevelName(e.FailOn),
+	)
+}
+
+// ExitCode returns the exit code the CLI should exit with
+// for validation diagnostics.
+func (e *DiagnosticsError) ExitCode() int {
+	return ExitCodeDiagnostics
+}
+
+// CheckDiagnostics returns a DiagnosticsError when any diagnostics were
+// received at or above the provided fail on level.
+func CheckDiagnostics(counts *DiagnosticCounts, failOn core.DiagnosticLevel) error {
+	if counts.AtOrAbove(failOn) == 0 {
+		return nil
+	}
+
+	return &DiagnosticsError{
+		Counts: counts,
+		FailOn: failOn,
+	}
+}
@@ -0,0 +1,292 @@
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+)
+
+const (
+	// snippetContextLines is the number of lines shown before
+	// and after the lines a diagnostic refers to.
+	snippetContextLines = 1
+	// snippetMaxHighlightedLines is the maximum number of lines that are
+	// shown for a diagnostic range that spans multiple lines,
+	// lines in the middle of larger ranges are omitted.
+	snippetMaxHighlightedLines = 6
+)
+
+// Source holds the contents of a blueprint file on the local file system
+// that is used to show the lines of the blueprint that diagnostics refer to.
+type Source struct {
+	// File is the blueprint file as it was provided by the user.
+	File    string
+	absPath string
+	lines   []string
+}
+
+// LoadSource loads a blueprint file so source snippets can be rendered for
+// diagnostics, nil is returned for remote blueprint files and local files
+// that can not be read as snippets are only an aid to the user.
+func LoadSource(blueprintFile string) *Source {
+	location, err := blueprint.ParseLocation(blueprintFile)
+	if err != nil || location.IsRemote() {
+		return nil
+	}
+
+	absPath, err := filepath.Abs(location.Path)
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil
+	}
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	// A trailing new line ends the last line rather than starting a new line.
+	content = strings.TrimSuffix(content, "\n")
+	return &Source{
+		File:    blueprintFile,
+		absPath: absPath,
+		lines:   strings.Split(content, "\n"),
+	}
+}
+
+// Path returns the absolute path of the blueprint file on the local file system.
+func (s *Source) Path() string {
+	return s.absPath
+}
+
+// SnippetStyle holds optional functions used to style the parts of
+// a source snippet, such as colours in the interactive UI.
+type SnippetStyle struct {
+	// Gutter styles the line numbers and separators.
+	Gutter func(string) string
+	// Highlight styles the carets that underline the range of a diagnostic.
+	Highlight func(string) string
+}
+
+func (s *SnippetStyle) gutter(value string) string {
+	if s == nil || s.Gutter == nil {
+		return value
+	}
+	return s.Gutter(value)
+}
+
+func (s *SnippetStyle) highlight(value string) string {
+	if s == nil || s.Highlight == nil {
+		return value
+	}
+	return s.Highlight(value)
+}
+
+// Location returns the location of the start of a diagnostic range in the
+// form "file:line:column", when hyperlinks is true, the location is a
+// clickable OSC 8 hyperlink to the blueprint file for terminals that support it.
+func (s *Source) Location(r *core.DiagnosticRange, hyperlinks bool) string {
+	location := fmt.Sprintf("%s:%d:%d", s.File, r.Start.Line, r.Start.Column)
+	if !hyperlinks {
+		return location
+	}
+
+	return Hyperlink(fileURL(s.absPath), location)
+}
+
+// Render renders the location of a diagnostic range followed by the lines
+// of the blueprint file that the range refers to, with the line numbers
+// in a gutter and carets underlining the range, along with the surrounding
+// lines for context, in a similar way to compilers such as rustc.
+// An empty string is returned when the range does not refer to lines
+// in the blueprint file.
+func (s *Source) Render(r *core.DiagnosticRange, hyperlinks bool, style *SnippetStyle) string {
+	if !HasPreciseRange(r) || r.Start.Line > len(s.lines) {
+		return ""
+	}
+
+	startLine, startColumn := r.Start.Line, r.Start.Column
+	endLine, endColumn := s.normaliseEnd(r)
+
+	firstLine := max(startLine-snippetContextLines, 1)
+	lastLine := min(endLine+snippetContextLines, len(s.lines))
+	gutterWidth := len(fmt.Sprint(lastLine))
+	emptyGutter := style.gutter(strings.Repeat(" ", gutterWidth) + " |")
+
+	sb := strings.Builder{}
+	sb.WriteString(style.gutter(strings.Repeat(" ", gutterWidth) + "--> "))
+	sb.WriteString(s.Location(r, hyperlinks))
+	sb.WriteString("\n")
+	sb.WriteString(emptyGutter)
+	sb.WriteString("\n")
+	for line := firstLine; line <= lastLine; line += 1 {
+		isHighlighted := line >= startLine && line <= endLine
+		if isHighlighted && isOmittedLine(line, startLine, endLine) {
+			if line == startLine+snippetMaxHighlightedLines-2 {
+				sb.WriteString(style.gutter(strings.Repeat(".", gutterWidth) + " |"))
+				sb.WriteString("\n")
+			}
+			continue
+		}
+
+		text := s.lines[line-1]
+		sb.WriteString(style.gutter(fmt.Sprintf("%*d |", gutterWidth, line)))
+		if text != "" {
+			sb.WriteString(" ")
+			sb.WriteString(text)
+		}
+		sb.WriteString("\n")
+
+		// Blank lines within a range that spans multiple lines
+		// have nothing to underline.
+		if !isHighlighted || (line != startLine && strings.TrimSpace(text) == "") {
+			continue
+		}
+
+		from := firstNonSpaceColumn(text)
+		if line == startLine {
+			from = startColumn
+		}
+		to := len([]rune(text)) + 1
+		if line == endLine {
+			to = endColumn
+		}
+		if caretLine := carets(text, from, to); caretLine != "" {
+			sb.WriteString(emptyGutter)
+			sb.WriteString(" ")
+			sb.WriteString(style.highlight(caretLine))
+			sb.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// normaliseEnd returns the last line and the exclusive end column of a
+// diagnostic range in the source.
+// The end of a range is often the start of the next element in the blueprint,
+// so a range that ends at the start of a line ends on the previous line
+// and trailing blank lines are not included.
+func (s *Source) normaliseEnd(r *core.DiagnosticRange) (int, int) {
+	startLine, startColumn := r.Start.Line, r.Start.Column
+	endLine, endColumn := endPosition(r)
+	if endLine > len(s.lines) {
+		endLine = len(s.lines)
+		endColumn = len([]rune(s.lines[endLine-1])) + 1
+	}
+
+	if endLine > startLine && endColumn <= 1 {
+		endLine -= 1
+		endColumn = len([]rune(s.lines[endLine-1])) + 1
+	}
+
+	for endLine > startLine && strings.TrimSpace(s.lines[endLine-1]) == "" {
+		endLine -= 1
+		endColumn = len([]rune(s.lines[endLine-1])) + 1
+	}
+
+	if endLine < startLine || (endLine == startLine && endColumn <= startColumn) {
+		return startLine, startColumn + 1
+	}
+
+	return endLine, endColumn
+}
+
+// isOmittedLine determines whether a line in a range that spans more than
+// the maximum number of highlighted lines should be left out of the snippet,
+// the first and last lines of the range are always shown.
+func isOmittedLine(line int, startLine int, endLine int) bool {
+	if endLine-startLine+1 <= snippetMaxHighlightedLines {
+		return false
+	}
+
+	return line >= startLine+snippetMaxHighlightedLines-2 && line < endLine-1
+}
+
+// carets produces a line with carets under the characters of the provided
+// line of text from the start column up to the exclusive end column.
+// Tabs before the start column are kept so the carets line up
+// with the text in the same way it is displayed.
+func carets(text string, from int, to int) string {
+	runes := []rune(text)
+	from = max(from, 1)
+	to = min(to, len(runes)+1)
+	if from > len(runes)+1 {
+		return ""
+	}
+	if to <= from {
+		to = from + 1
+	}
+
+	sb := strings.Builder{}
+	for i := 0; i < from-1; i += 1 {
+		if runes[i] == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	sb.WriteString(strings.Repeat("^", to-from))
+	return sb.String()
+}
+
+func firstNonSpaceColumn(text string) int {
+	for i, char := range []rune(text) {
+		if !unicode.IsSpace(char) {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// Hyperlink wraps the provided text in an OSC 8 escape sequence so that
+// terminals that support hyperlinks display it as a link to the provided URL,
+// terminals without support for hyperlinks display the text as it is.
+func Hyperlink(url string, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+func fileURL(absPath string) string {
+	urlPath := filepath.ToSlash(absPath)
+	// Windows paths such as "C:/path/to/file" need a leading slash
+	// to be a valid file URL path.
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+
+	fileURL := &url.URL{Scheme: "file", Path: urlPath}
+	return fileURL.String()
+}
+
+// DiagnosticWithSource produces a plain text representation of a diagnostic
+// that includes the location and a snippet of the lines of the blueprint
+// file that the diagnostic refers to.
+// The single line representation from DiagnosticToPlainText is used when
+// the source is not available or the diagnostic does not have a precise range.
+func DiagnosticWithSource(
+	diagnostic *core.Diagnostic,
+	source *Source,
+	hyperlinks bool,
+) string {
+	if source == nil || !HasPreciseRange(diagnostic.Range) {
+		return DiagnosticToPlainText(diagnostic)
+	}
+
+	rendered := source.Render(diagnostic.Range, hyperlinks, nil)
+	if rendered == "" {
+		return DiagnosticToPlainText(diagnostic)
+	}
+
+	return fmt.Sprintf(
+		"%s: %s\n%s",
+		DiagnosticLevelName(diagnostic.Level),
+		diagnostic.Message,
+		rendered,
+	)
+}
@@ -1 +1,183 @@
 package validate
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/newstack-cloud/bluelink/libs/blueprint/core"
+)
+
+// Format is the format used to write blueprint validation diagnostics
+// to the output of the validate command.
+type Format string
+
+const (
+	// FormatText writes diagnostics in a human-readable format.
+	FormatText Format = "text"
+	// FormatJSON writes diagnostics as a JSON document.
+	FormatJSON Format = "json"
+	// FormatSARIF writes diagnostics as a SARIF v2.1.0 log
+	// that can be uploaded to code scanning tools.
+	FormatSARIF Format = "sarif"
+	// FormatJUnit writes diagnostics as a JUnit XML test report.
+	FormatJUnit Format = "junit"
+	// FormatGitHub writes diagnostics as GitHub Actions workflow commands
+	// so they are shown as annotations on pull requests.
+	FormatGitHub Format = "github"
+	// FormatCheckstyle writes diagnostics as a Checkstyle XML report.
+	FormatCheckstyle Format = "checkstyle"
+)
+
+var (
+	// SupportedFormats is a list of all the formats supported
+	// for blueprint validation diagnostics.
+	SupportedFormats = []Format{
+		FormatText,
+		FormatJSON,
+		FormatSARIF,
+		FormatJUnit,
+		FormatGitHub,
+		FormatCheckstyle,
+	}
+)
+
+// ParseFormat parses and validates a diagnostic output format provided by the user.
+func ParseFormat(value string) (Format, error) {
+	format := Format(value)
+	if slices.Contains(SupportedFormats, format) {
+		return format, nil
+	}
+
+	supported := []string{}
+	for _, supportedFormat := range SupportedFormats {
+		supported = append(supported, fmt.Sprintf("%q", supportedFormat))
+	}
+
+	return "", fmt.Errorf(
+		"unsupported validation output format %q, must be one of %s",
+		value,
+		strings.Join(supported, ", "),
+	)
+}
+
+const (
+	// HyperlinksAuto writes hyperlinks when the output is a terminal.
+	HyperlinksAuto = "auto"
+	// HyperlinksAlways always writes hyperlinks, this is useful for
+	// CI systems that render hyperlinks in logs.
+	HyperlinksAlways = "always"
+	// HyperlinksNever never writes hyperlinks.
+	HyperlinksNever = "never"
+)
+
+// ParseHyperlinks determines whether file locations in diagnostics
+// should be written as hyperlinks from the value provided by the user
+// and whether the output is a terminal.
+func ParseHyperlinks(value string, inTerminal bool) (bool, error) {
+	switch value {
+	case HyperlinksAuto:
+		return inTerminal, nil
+	case HyperlinksAlways:
+		return true, nil
+	case HyperlinksNever:
+		return false, nil
+	}
+
+	return false, fmt.Errorf(
+		"unsupported hyperlinks value %q, must be one of %q, %q or %q",
+		value,
+		HyperlinksAuto,
+		HyperlinksAlways,
+		HyperlinksNever,
+	)
+}
+
+// WriteDiagnostics writes the diagnostics produced by validating the provided
+// blueprint files in the given format as a single document.
+// Blueprint files that could not be validated are reported
+// as an error diagnostic for the file.
+func WriteDiagnostics(
+	writer io.Writer,
+	format Format,
+	results []*FileResult,
+) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(writer, results)
+	case FormatSARIF:
+		return writeSARIF(writer, results)
+	case FormatJUnit:
+		return writeJUnit(writer, results)
+	case FormatGitHub:
+		return writeGitHub(writer, results)
+	case FormatCheckstyle:
+		return writeCheckstyle(writer, results)
+	default:
+		return writeText(writer, results)
+	}
+}
+
+// DiagnosticLevelName returns the name of a diagnostic level
+// to display to the user.
+func DiagnosticLevelName(level core.DiagnosticLevel) string {
+	switch level {
+	case core.DiagnosticLevelError:
+		return "error"
+	case core.DiagnosticLevelWarning:
+		return "warning"
+	case core.DiagnosticLevelInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// HasPreciseRange determines whether a diagnostic range points to
+// a specific line and column in the source blueprint document.
+func HasPreciseRange(r *core.DiagnosticRange) bool {
+	return r != nil && r.Start != nil && r.Start.Line > 0 && r.Start.Column > 0
+}
+
+// DiagnosticToPlainText produces a single line plain text
+// representation of a diagnostic.
+func DiagnosticToPlainText(diagnostic *core.Diagnostic) string {
+	sb := strings.Builder{}
+	sb.WriteString(DiagnosticLevelName(diagnostic.Level))
+	sb.WriteString(": ")
+	sb.WriteString(diagnostic.Message)
+	if HasPreciseRange(diagnostic.Range) {
+		sb.WriteString(
+			fmt.Sprintf(" (line %d, column %d)", diagnostic.Range.Start.Line, diagnostic.Range.Start.Column),
+		)
+	}
+	return sb.String()
+}
+
+func writeText(writer io.Writer, results []*FileResult) error {
+	for _, result := range results {
+		if len(results) > 1 {
+			if _, err := fmt.Fprintf(writer, "%s:\n", result.BlueprintFile); err != nil {
+				return err
+			}
+		}
+		for _, diagnostic := range withFileErrors(result) {
+			if _, err := fmt.Fprintln(writer, DiagnosticToPlainText(diagnostic)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// endPosition returns the end line and column of a range,
+// falling back to the start of the range when no end position is known.
+func endPosition(r *core.DiagnosticRange) (int, int) {
+	if r.End != nil && r.End.Line > 0 {
+		return r.End.Line, r.End.Column
+	}
+
+	return r.Start.Line, r.Start.Column
+}
@@ -0,0 +1,31 @@
+package validate
+
+import (
+	"github.com/newstack-cloud/celerity/apps/cli/internal/blueprint"
+)
+
+// WatchedFiles returns the local files that are watched for changes
+// in watch mode, this includes the blueprint file, the child blueprints it
+// includes from the local file system and the files in Options.WatchFiles.
+// Watch mode is only supported for a single blueprint file.
+// Remote blueprint files can not be watched, only the other files are
+// returned for remote blueprint files.
+// The error returned when child blueprints can not be resolved is
+// intended to be logged, the blueprint file and the other files are still
+// returned so they can be watched for a fix.
+func WatchedFiles(opts *Options) ([]string, error) {
+	files := append([]string{}, opts.WatchFiles...)
+
+	if len(opts.BlueprintFiles) != 1 {
+		return files, nil
+	}
+
+	location, err := blueprint.ParseLocation(opts.BlueprintFiles[0])
+	if err != nil || location.IsRemote() {
+		return files, err
+	}
+
+	files = append(files, location.Path)
+	includes, err := blueprint.LocalIncludes(location.Path)
+	return append(files, includes...), err
+}
@@ -0,0 +1,172 @@
+package watch
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// DefaultDebounce is the default amount of time to wait after the last
+	// change to a watched file before changes are reported.
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// Watcher watches a set of files for changes.
+// Changes are debounced so a burst of writes, such as an editor writing
+// to a temporary file and renaming it, is reported as a single change.
+//
+// The directories containing the watched files are watched instead of the
+// files themselves so files that are replaced or do not exist yet are
+// picked up when they are written.
+type Watcher struct {
+	fsWatcher *fsnotify.Watcher
+	debounce  time.Duration
+	mu        sync.Mutex
+	files     map[string]bool
+	dirs      map[string]bool
+	changes   chan []string
+	errs      chan error
+	done      chan struct{}
+}
+
+// New creates a new watcher that reports changes once no further changes
+// have been made to the watched files for the provided debounce duration.
+func New(debounce time.Duration) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &Watcher{
+		fsWatcher: fsWatcher,
+		debounce:  debounce,
+		files:     map[string]bool{},
+		dirs:      map[string]bool{},
+		changes:   make(chan []string),
+		errs:      make(chan error),
+		done:      make(chan struct{}),
+	}
+	go watcher.run()
+	return watcher, nil
+}
+
+// SetFiles replaces the set of files that are watched.
+func (w *Watcher) SetFiles(paths []string) error {
+	files := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		files[absPath] = true
+		dirs[filepath.Dir(absPath)] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			// The directory may have been removed, in which case
+			// it is no longer watched anyway.
+			_ = w.fsWatcher.Remove(dir)
+		}
+	}
+
+	for dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsWatcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	w.files = files
+	w.dirs = dirs
+	return nil
+}
+
+// Changes returns a channel that receives the sorted paths of the
+// watched files that have changed once changes have settled.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Errors returns a channel that receives errors from
+// the underlying file system watcher.
+func (w *Watcher) Errors() <-chan error {
+	return w.errs
+}
+
+// Close stops watching for changes.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsWatcher.Close()
+}
+
+func (w *Watcher) run() {
+	changed := map[string]bool{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+		case event, open := <-w.fsWatcher.Events:
+			if !open {
+				return
+			}
+			if w.isWatched(event) {
+				changed[filepath.Clean(event.Name)] = true
+				timer.Reset(w.debounce)
+			}
+		case err, open := <-w.fsWatcher.Errors:
+			if !open {
+				return
+			}
+			select {
+			case w.errs <- err:
+			case <-w.done:
+				return
+			}
+		case <-timer.C:
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			changed = map[string]bool{}
+
+			select {
+			case w.changes <- paths:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) isWatched(event fsnotify.Event) bool {
+	// Changes to file permissions are not relevant
+	// to the contents of a watched file.
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+
+	absPath, err := filepath.Abs(event.Name)
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.files[absPath]
+}